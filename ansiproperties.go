@@ -25,6 +25,15 @@ const (
 	Color24Bit
 )
 
+// colorDepth selects which escape sequences are written for fg/bg colors.
+type colorDepth uint8
+
+const (
+	colorDepth256 colorDepth = iota // \033[38;5;Nm
+	colorDepth16                    // \033[31m, \033[91m etc. using the nearest of the first 16 colors
+	colorDepth24                    // \033[38;2;R;G;Bm
+)
+
 var (
 	// map of strings to 8 bit color codes — accessed via atomic pointer for lock-free reads
 	defaultColorAliases = map[string]int{
@@ -69,6 +78,12 @@ var (
 	ansiFgSeq [256]string
 	ansiBgSeq [256]string
 
+	// Pre-computed sequences for the 16 color and 24 bit color depths
+	ansi16FgSeq [256]string
+	ansi16BgSeq [256]string
+	ansi24FgSeq [256]string
+	ansi24BgSeq [256]string
+
 	// Pre-computed HTML color style fragments, e.g. "color:#ff0000;"
 	htmlFgStyle [256]string
 	htmlBgStyle [256]string
@@ -103,6 +118,7 @@ type ansiProperties struct {
 	clear    int
 	position []uint16
 	htmlOnly bool
+	depth    colorDepth
 }
 
 // propertiesPool recycles ansiProperties to reduce heap allocations.
@@ -119,6 +135,7 @@ func acquireProperties() *ansiProperties {
 	p.clear = -1
 	p.position = p.position[:0]
 	p.htmlOnly = false
+	p.depth = colorDepth256
 	return p
 }
 
@@ -130,21 +147,33 @@ func (p *ansiProperties) AnsiReset() string {
 	return ansiResetAll
 }
 
-func (p ansiProperties) PropagateAnsiCode(previous *ansiProperties) string {
+// resolveColors returns the fg and bg in effect for p when it is opened inside
+// previous. Unset colors are inherited from the immediate parent only, which
+// mirrors what PropagateAnsiCode writes to the terminal.
+func (p *ansiProperties) resolveColors(previous *ansiProperties) (fg int, bg int) {
 
-	origFg := p.fg
-	origBg := p.bg
+	fg, bg = p.fg, p.bg
 
 	if previous != nil {
 
-		if p.fg == defaultFg256 {
-			p.fg = previous.fg
+		if fg == defaultFg256 {
+			fg = previous.fg
 		}
-		if p.bg == defaultBg256 {
-			p.bg = previous.bg
+		if bg == defaultBg256 {
+			bg = previous.bg
 		}
 	}
 
+	return fg, bg
+}
+
+func (p ansiProperties) PropagateAnsiCode(previous *ansiProperties) string {
+
+	origFg := p.fg
+	origBg := p.bg
+
+	p.fg, p.bg = p.resolveColors(previous)
+
 	if p.htmlOnly {
 
 		if previous != nil {
@@ -180,19 +209,27 @@ func (p ansiProperties) PropagateAnsiCode(previous *ansiProperties) string {
 		positionCode = "\033[" + strconv.Itoa(int(p.position[1])) + ";" + strconv.Itoa(int(p.position[0])) + "H"
 	}
 
+	fgSeq, bgSeq := &ansiFgSeq, &ansiBgSeq
+	switch p.depth {
+	case colorDepth16:
+		fgSeq, bgSeq = &ansi16FgSeq, &ansi16BgSeq
+	case colorDepth24:
+		fgSeq, bgSeq = &ansi24FgSeq, &ansi24BgSeq
+	}
+
 	var colorCode string = ""
 
 	if p.fg == defaultFg256 && p.bg == defaultBg256 {
 		colorCode = "\033[0m"
 	} else {
 		if p.fg > -1 {
-			colorCode += ansiFgSeq[p.fg]
+			colorCode += fgSeq[p.fg]
 		} else if origFg == defaultFg256 {
 			colorCode += "\033[39m"
 		}
 
 		if p.bg > -1 {
-			colorCode += ansiBgSeq[p.bg]
+			colorCode += bgSeq[p.bg]
 		} else if origBg == defaultBg256 {
 			colorCode += "\033[49m"
 		}
@@ -205,16 +242,12 @@ func SetColorMode(mode ColorMode) {
 	// This is a NOOP now, left for backwards compatibility
 }
 
-// extractProperties parses an open tag string like `<ansi fg=red bg="0" >` and
-// returns a populated ansiProperties. The caller is responsible for releasing
-// the returned pointer via releaseProperties when it is no longer needed.
-func extractProperties(tagStr string) *ansiProperties {
+// nextAttribute reads the key/value pair of an open tag string like
+// `<ansi fg=red bg="0" >` that follows position i, with quotes around the
+// value removed. It returns the position to continue reading from, and
+// ok=false once there are no more attributes.
+func nextAttribute(tagStr string, i int) (key string, val string, next int, ok bool) {
 
-	ret := acquireProperties()
-
-	aliases := loadAliasSnapshot()
-
-	i := 0
 	n := len(tagStr)
 
 	for i < n {
@@ -233,7 +266,7 @@ func extractProperties(tagStr string) *ansiProperties {
 		if i >= n {
 			break
 		}
-		key := tagStr[keyStart:i]
+		key = tagStr[keyStart:i]
 		i++ // consume '='
 
 		// Read the value, optionally quoted with ' or "
@@ -255,11 +288,34 @@ func extractProperties(tagStr string) *ansiProperties {
 				i++
 			}
 		}
-		val := tagStr[valStart:i]
+		val = tagStr[valStart:i]
 		if quote != 0 && i < n {
 			i++ // consume closing quote
 		}
 
+		return key, val, i, true
+	}
+
+	return "", "", n, false
+}
+
+// extractProperties parses an open tag string like `<ansi fg=red bg="0" >` and
+// returns a populated ansiProperties. The caller is responsible for releasing
+// the returned pointer via releaseProperties when it is no longer needed.
+func extractProperties(tagStr string) *ansiProperties {
+
+	ret := acquireProperties()
+
+	aliases := loadAliasSnapshot()
+
+	for i := 0; i < len(tagStr); {
+
+		key, val, next, ok := nextAttribute(tagStr, i)
+		if !ok {
+			break
+		}
+		i = next
+
 		if len(val) == 0 {
 			continue
 		}
@@ -19,9 +19,12 @@ const (
 	parseModeNone     parseMode = 0
 	parseModeMatching parseMode = 1
 
-	StripTags  ParseBehavior = iota // remove all valid ansitags
-	Monochrome                      // ignore any color changing properties
-	HTML                            // produce HTML instead of ansi tags
+	StripTags     ParseBehavior = iota // remove all valid ansitags
+	Monochrome                         // ignore any color changing properties
+	HTML                               // produce HTML instead of ansi tags
+	Color16                            // downsample colors to the basic 16 color escape codes
+	TrueColor                          // write colors as 24 bit escape codes
+	StripControls                      // remove raw escape sequences and control characters from the text between tags, see SanitizeControls
 
 	// maxTagSize is the maximum byte length of a tag we will accumulate.
 	// Tags longer than this cannot be valid, so we flush and reset.
@@ -29,8 +32,9 @@ const (
 )
 
 var (
-	tagStart byte = '<'
-	tagEnd   byte = '>'
+	tagStart  byte = '<'
+	tagEnd    byte = '>'
+	tagEscape byte = '\\' // a tagStart followed by tagEscape is a literal tagStart
 
 	tagOpen  string = "ansi"  // will be wrapped in tagStart and tagEnd
 	tagClose string = "/ansi" // will be wrapped in tagStart and tagEnd
@@ -40,19 +44,27 @@ func Parse(str string, behaviors ...ParseBehavior) string {
 
 	var outputBuffer bytes.Buffer
 	outputBuffer.Grow(len(str))
-	parseString(str, &outputBuffer, behaviors...)
+	parseString(str, &outputBuffer, nil, behaviors...)
 	return outputBuffer.String()
 }
 
 // parseString is the core implementation for string input, avoiding bufio overhead.
-func parseString(str string, out *bytes.Buffer, behaviors ...ParseBehavior) {
+// It uses the limits set with SetLimits unless custom limits are given.
+func parseString(str string, out *bytes.Buffer, custom *Limits, behaviors ...ParseBehavior) error {
 
 	rwLock.RLock()
 	defer rwLock.RUnlock()
 
+	lim := limiter{Limits: limits}
+	if custom != nil {
+		lim.Limits = *custom
+	}
+
 	var stripAllTags bool
 	var stripAllColor bool
 	var writeHTML bool
+	var depth colorDepth = colorDepth256
+	var controls *textFilter
 
 	for _, b := range behaviors {
 		switch b {
@@ -62,6 +74,12 @@ func parseString(str string, out *bytes.Buffer, behaviors ...ParseBehavior) {
 			stripAllColor = true
 		case HTML:
 			writeHTML = true
+		case Color16:
+			depth = colorDepth16
+		case TrueColor:
+			depth = colorDepth24
+		case StripControls:
+			controls = &textFilter{filter: controlFilter{all: true}}
 		}
 	}
 
@@ -81,7 +99,20 @@ func parseString(str string, out *bytes.Buffer, behaviors ...ParseBehavior) {
 
 		if mode == parseModeNone {
 			if input != tagStart {
-				out.WriteByte(input)
+				if controls != nil {
+					controls.write(out, input)
+				} else {
+					out.WriteByte(input)
+				}
+				continue
+			}
+			if i+1 < len(str) && str[i+1] == tagEscape {
+				if controls != nil {
+					controls.write(out, input)
+				} else {
+					out.WriteByte(input)
+				}
+				i++
 				continue
 			}
 			mode = parseModeMatching
@@ -113,20 +144,33 @@ func parseString(str string, out *bytes.Buffer, behaviors ...ParseBehavior) {
 				if writeHTML {
 					newTag.htmlOnly = true
 				}
+				newTag.depth = depth
 
 				tagLen = 0
+				lim.read = i + 1
+				if controls != nil {
+					controls.flush(out)
+				}
 
+				var code string
 				if !stripAllTags {
-					stackLen := len(tagStack)
-					if stackLen > 0 {
-						out.WriteString(newTag.PropagateAnsiCode(tagStack[stackLen-1]))
+					if stackLen := len(tagStack); stackLen > 0 {
+						code = newTag.PropagateAnsiCode(tagStack[stackLen-1])
 					} else {
-						out.WriteString(newTag.PropagateAnsiCode(nil))
+						code = newTag.PropagateAnsiCode(nil)
 					}
+				}
+
+				result := lim.tag(true, len(tagStack), len(code))
+				if result == limitApply && !stripAllTags {
+					out.WriteString(code)
 					tagStack = append(tagStack, newTag)
 				} else {
 					releaseProperties(newTag)
 				}
+				if result == limitStop {
+					break
+				}
 
 				mode = parseModeNone
 				openMatcher.Reset()
@@ -147,20 +191,32 @@ func parseString(str string, out *bytes.Buffer, behaviors ...ParseBehavior) {
 				}
 
 				tagLen = 0
-				if !stripAllTags {
-					stackLen := len(tagStack)
+				lim.read = i + 1
+				if controls != nil {
+					controls.flush(out)
+				}
+
+				stackLen := len(tagStack)
 
+				var code string
+				if !stripAllTags {
 					if stackLen > 2 {
-						out.WriteString(tagStack[stackLen-2].PropagateAnsiCode(tagStack[stackLen-3]))
+						code = tagStack[stackLen-2].PropagateAnsiCode(tagStack[stackLen-3])
 					} else if stackLen > 1 {
-						out.WriteString(tagStack[stackLen-2].PropagateAnsiCode(nil))
+						code = tagStack[stackLen-2].PropagateAnsiCode(nil)
+					} else if writeHTML {
+						code = htmlResetAll
 					} else {
-						if writeHTML {
-							out.WriteString(htmlResetAll)
-						} else {
-							out.WriteString(ansiResetAll)
-						}
+						code = ansiResetAll
 					}
+				}
+
+				result := lim.tag(false, stackLen, len(code))
+				if result == limitStop {
+					break
+				}
+				if result == limitApply && !stripAllTags {
+					out.WriteString(code)
 
 					if stackLen > 0 {
 						releaseProperties(tagStack[stackLen-1])
@@ -186,7 +242,11 @@ func parseString(str string, out *bytes.Buffer, behaviors ...ParseBehavior) {
 			mode = parseModeNone
 
 			if !stripAllTags {
-				out.Write(tagBuf[:tagLen])
+				if controls != nil {
+					controls.write(out, tagBuf[:tagLen]...)
+				} else {
+					out.Write(tagBuf[:tagLen])
+				}
 			}
 			tagLen = 0
 			continue
@@ -195,9 +255,16 @@ func parseString(str string, out *bytes.Buffer, behaviors ...ParseBehavior) {
 
 	if !stripAllTags {
 		if tagLen > 0 {
-			out.Write(tagBuf[:tagLen])
+			if controls != nil {
+				controls.write(out, tagBuf[:tagLen]...)
+			} else {
+				out.Write(tagBuf[:tagLen])
+			}
 			tagLen = 0
 		}
+		if controls != nil {
+			controls.flush(out)
+		}
 
 		if len(tagStack) > 0 {
 			if writeHTML {
@@ -214,6 +281,8 @@ func parseString(str string, out *bytes.Buffer, behaviors ...ParseBehavior) {
 			releaseProperties(p)
 		}
 	}
+
+	return lim.err()
 }
 
 func ParseStreaming(inbound *bufio.Reader, outbound *bufio.Writer, behaviors ...ParseBehavior) {
@@ -221,9 +290,13 @@ func ParseStreaming(inbound *bufio.Reader, outbound *bufio.Writer, behaviors ...
 	rwLock.RLock()
 	defer rwLock.RUnlock()
 
+	lim := limiter{Limits: limits}
+
 	var stripAllTags bool
 	var stripAllColor bool
 	var writeHTML bool
+	var depth colorDepth = colorDepth256
+	var controls *textFilter
 
 	for _, b := range behaviors {
 		switch b {
@@ -233,6 +306,12 @@ func ParseStreaming(inbound *bufio.Reader, outbound *bufio.Writer, behaviors ...
 			stripAllColor = true
 		case HTML:
 			writeHTML = true
+		case Color16:
+			depth = colorDepth16
+		case TrueColor:
+			depth = colorDepth24
+		case StripControls:
+			controls = &textFilter{filter: controlFilter{all: true}}
 		}
 	}
 
@@ -252,10 +331,24 @@ func ParseStreaming(inbound *bufio.Reader, outbound *bufio.Writer, behaviors ...
 		if err != nil && err == io.EOF {
 			break
 		}
+		lim.read++
 
 		if mode == parseModeNone {
 			if input != tagStart {
-				outbound.WriteByte(input)
+				if controls != nil {
+					controls.write(outbound, input)
+				} else {
+					outbound.WriteByte(input)
+				}
+				continue
+			}
+			if next, err := inbound.Peek(1); err == nil && next[0] == tagEscape {
+				inbound.ReadByte()
+				if controls != nil {
+					controls.write(outbound, input)
+				} else {
+					outbound.WriteByte(input)
+				}
 				continue
 			}
 			mode = parseModeMatching
@@ -287,20 +380,32 @@ func ParseStreaming(inbound *bufio.Reader, outbound *bufio.Writer, behaviors ...
 				if writeHTML {
 					newTag.htmlOnly = true
 				}
+				newTag.depth = depth
 
 				tagLen = 0
+				if controls != nil {
+					controls.flush(outbound)
+				}
 
+				var code string
 				if !stripAllTags {
-					stackLen := len(tagStack)
-					if stackLen > 0 {
-						outbound.WriteString(newTag.PropagateAnsiCode(tagStack[stackLen-1]))
+					if stackLen := len(tagStack); stackLen > 0 {
+						code = newTag.PropagateAnsiCode(tagStack[stackLen-1])
 					} else {
-						outbound.WriteString(newTag.PropagateAnsiCode(nil))
+						code = newTag.PropagateAnsiCode(nil)
 					}
+				}
+
+				result := lim.tag(true, len(tagStack), len(code))
+				if result == limitApply && !stripAllTags {
+					outbound.WriteString(code)
 					tagStack = append(tagStack, newTag)
 				} else {
 					releaseProperties(newTag)
 				}
+				if result == limitStop {
+					break
+				}
 
 				mode = parseModeNone
 				openMatcher.Reset()
@@ -321,20 +426,31 @@ func ParseStreaming(inbound *bufio.Reader, outbound *bufio.Writer, behaviors ...
 				}
 
 				tagLen = 0
-				if !stripAllTags {
-					stackLen := len(tagStack)
+				if controls != nil {
+					controls.flush(outbound)
+				}
+
+				stackLen := len(tagStack)
 
+				var code string
+				if !stripAllTags {
 					if stackLen > 2 {
-						outbound.WriteString(tagStack[stackLen-2].PropagateAnsiCode(tagStack[stackLen-3]))
+						code = tagStack[stackLen-2].PropagateAnsiCode(tagStack[stackLen-3])
 					} else if stackLen > 1 {
-						outbound.WriteString(tagStack[stackLen-2].PropagateAnsiCode(nil))
+						code = tagStack[stackLen-2].PropagateAnsiCode(nil)
+					} else if writeHTML {
+						code = htmlResetAll
 					} else {
-						if writeHTML {
-							outbound.WriteString(htmlResetAll)
-						} else {
-							outbound.WriteString(ansiResetAll)
-						}
+						code = ansiResetAll
 					}
+				}
+
+				result := lim.tag(false, stackLen, len(code))
+				if result == limitStop {
+					break
+				}
+				if result == limitApply && !stripAllTags {
+					outbound.WriteString(code)
 
 					if stackLen > 0 {
 						releaseProperties(tagStack[stackLen-1])
@@ -360,7 +476,11 @@ func ParseStreaming(inbound *bufio.Reader, outbound *bufio.Writer, behaviors ...
 			mode = parseModeNone
 
 			if !stripAllTags {
-				outbound.Write(tagBuf[:tagLen])
+				if controls != nil {
+					controls.write(outbound, tagBuf[:tagLen]...)
+				} else {
+					outbound.Write(tagBuf[:tagLen])
+				}
 			}
 			tagLen = 0
 			continue
@@ -369,9 +489,16 @@ func ParseStreaming(inbound *bufio.Reader, outbound *bufio.Writer, behaviors ...
 
 	if !stripAllTags {
 		if tagLen > 0 {
-			outbound.Write(tagBuf[:tagLen])
+			if controls != nil {
+				controls.write(outbound, tagBuf[:tagLen]...)
+			} else {
+				outbound.Write(tagBuf[:tagLen])
+			}
 			tagLen = 0
 		}
+		if controls != nil {
+			controls.flush(outbound)
+		}
 
 		if len(tagStack) > 0 {
 			if writeHTML {
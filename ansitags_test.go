@@ -170,6 +170,78 @@ func TestParseMono(t *testing.T) {
 
 }
 
+func TestParseColor16(t *testing.T) {
+
+	testTable := loadTestFile("testdata/ansitags_test_color16.yaml")
+
+	for name, testCase := range testTable {
+
+		t.Run(name, func(t *testing.T) {
+
+			output := Parse(testCase.Input, Color16)
+			assert.Equal(t, testCase.Expected, output)
+		})
+	}
+
+}
+
+func TestParseTrueColor(t *testing.T) {
+
+	testTable := loadTestFile("testdata/ansitags_test_truecolor.yaml")
+
+	for name, testCase := range testTable {
+
+		t.Run(name, func(t *testing.T) {
+
+			output := Parse(testCase.Input, TrueColor)
+			assert.Equal(t, testCase.Expected, output)
+		})
+	}
+
+}
+
+func TestParseEscape(t *testing.T) {
+
+	testTable := loadTestFile("testdata/ansitags_test_escape.yaml")
+
+	for name, testCase := range testTable {
+
+		t.Run(name, func(t *testing.T) {
+
+			output := Parse(testCase.Input)
+			assert.Equal(t, testCase.Expected, output)
+
+			var outputBuffer bytes.Buffer
+			writer := bufio.NewWriter(&outputBuffer)
+			ParseStreaming(bufio.NewReader(strings.NewReader(testCase.Input)), writer)
+			writer.Flush()
+			assert.Equal(t, testCase.Expected, outputBuffer.String())
+		})
+	}
+
+}
+
+func TestParseStripControls(t *testing.T) {
+
+	testTable := loadTestFile("testdata/ansitags_test_controls.yaml")
+
+	for name, testCase := range testTable {
+
+		t.Run(name, func(t *testing.T) {
+
+			output := Parse(testCase.Input, StripControls)
+			assert.Equal(t, testCase.Expected, output)
+
+			var outputBuffer bytes.Buffer
+			writer := bufio.NewWriter(&outputBuffer)
+			ParseStreaming(bufio.NewReader(strings.NewReader(testCase.Input)), writer, StripControls)
+			writer.Flush()
+			assert.Equal(t, testCase.Expected, outputBuffer.String())
+		})
+	}
+
+}
+
 func TestParseLarge(t *testing.T) {
 
 	testString := loadRawFile("testdata/ansitags_test_streaming.yaml")
@@ -0,0 +1,54 @@
+package ansitags
+
+import "strings"
+
+// BorderSet holds the characters used to draw a frame around a Box or Table.
+type BorderSet struct {
+	Horizontal  rune
+	Vertical    rune
+	TopLeft     rune
+	TopRight    rune
+	BottomLeft  rune
+	BottomRight rune
+	TopT        rune // where a column divider meets the top edge
+	BottomT     rune // where a column divider meets the bottom edge
+	LeftT       rune // where a row divider meets the left edge
+	RightT      rune // where a row divider meets the right edge
+	Cross       rune // where a row divider crosses a column divider
+}
+
+var (
+	BorderASCII   = BorderSet{'-', '|', '+', '+', '+', '+', '+', '+', '+', '+', '+'}
+	BorderSingle  = BorderSet{'─', '│', '┌', '┐', '└', '┘', '┬', '┴', '├', '┤', '┼'}
+	BorderDouble  = BorderSet{'═', '║', '╔', '╗', '╚', '╝', '╦', '╩', '╠', '╣', '╬'}
+	BorderRounded = BorderSet{'─', '│', '╭', '╮', '╰', '╯', '┬', '┴', '├', '┤', '┼'}
+)
+
+// styled wraps text in a tag built from style, see styleTag. An empty
+// style returns text as is.
+func styled(style string, text string) string {
+
+	open := styleTag(style)
+	if open == "" || text == "" {
+		return text
+	}
+
+	return open + text + "</ansi>"
+}
+
+// styleTag returns an opening tag built from style, which is either tag
+// attributes such as `fg="red" bg="blue"`, or a bare color alias or code
+// which sets the foreground. An empty style returns an empty string.
+func styleTag(style string) string {
+
+	style = strings.TrimSpace(style)
+	if style == "" {
+		return ""
+	}
+
+	if strings.Contains(style, "=") {
+		return "<ansi " + style + ">"
+	}
+
+	return `<ansi fg="` + style + `">`
+}
@@ -0,0 +1,85 @@
+package ansitags
+
+import "strings"
+
+// BoxOptions controls how Box frames its content.
+type BoxOptions struct {
+	Border      *BorderSet // characters to draw the frame with, default BorderSingle
+	Title       string     // tagged text shown in the top border
+	Padding     int        // spaces between the frame and the content on every side
+	BorderStyle string     // tag attributes or an alias for the frame, e.g. "cyan" or `fg="blue" bg="black"`
+	Width       int        // total width including the frame, 0 to fit the content
+}
+
+// Box draws a frame around tagged content. The content is word wrapped to
+// fit inside the frame, keeping its tags intact, and each line is padded out
+// to the inner width. The title, if any, is cut short to fit the top border.
+// The result is tagged text with a newline after each line.
+//
+// Usage:
+//
+//	fmt.Print(ansitags.Parse(ansitags.Box(questLog, ansitags.BoxOptions{
+//		Border:      &ansitags.BorderRounded,
+//		Title:       `<ansi fg="yellow">Quest Journal</ansi>`,
+//		Padding:     1,
+//		BorderStyle: "cyan",
+//		Width:       60,
+//	})))
+func Box(content string, opts BoxOptions) string {
+
+	border := BorderSingle
+	if opts.Border != nil {
+		border = *opts.Border
+	}
+
+	padding := opts.Padding
+	if padding < 0 {
+		padding = 0
+	}
+
+	inner := opts.Width - 2 - padding*2
+	if opts.Width <= 0 {
+		inner = VisibleWidth(content)
+		if titleWidth := VisibleWidth(opts.Title) + 4 - padding*2; opts.Title != "" && titleWidth > inner {
+			inner = titleWidth
+		}
+	}
+	if inner < 1 {
+		inner = 1
+	}
+
+	span := inner + padding*2
+	horizontal := string(border.Horizontal)
+
+	var out strings.Builder
+
+	// Top border, with the title set into it after one border character
+	if opts.Title != "" && span-3 >= 1 {
+		title := Truncate(opts.Title, span-3, "…")
+		rest := span - 3 - VisibleWidth(title)
+		out.WriteString(styled(opts.BorderStyle, string(border.TopLeft)+horizontal) + " " + title + " " +
+			styled(opts.BorderStyle, strings.Repeat(horizontal, rest)+string(border.TopRight)) + "\n")
+	} else {
+		out.WriteString(styled(opts.BorderStyle, string(border.TopLeft)+strings.Repeat(horizontal, span)+string(border.TopRight)) + "\n")
+	}
+
+	vertical := styled(opts.BorderStyle, string(border.Vertical))
+	pad := strings.Repeat(" ", padding)
+	blank := vertical + strings.Repeat(" ", span) + vertical + "\n"
+
+	for i := 0; i < padding; i++ {
+		out.WriteString(blank)
+	}
+
+	for _, line := range Wrap(content, inner, WrapOptions{}) {
+		out.WriteString(vertical + pad + PadRight(line, inner, ' ') + pad + vertical + "\n")
+	}
+
+	for i := 0; i < padding; i++ {
+		out.WriteString(blank)
+	}
+
+	out.WriteString(styled(opts.BorderStyle, string(border.BottomLeft)+strings.Repeat(horizontal, span)+string(border.BottomRight)) + "\n")
+
+	return out.String()
+}
@@ -0,0 +1,63 @@
+package ansitags
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBox(t *testing.T) {
+	expected := "" +
+		"┌─────┐\n" +
+		"│Hello│\n" +
+		"└─────┘\n"
+
+	assert.Equal(t, expected, Box("Hello", BoxOptions{}))
+}
+
+func TestBoxTitleAndPadding(t *testing.T) {
+	expected := "" +
+		"+- Map -------+\n" +
+		"|             |\n" +
+		"| The quick   |\n" +
+		"| brown fox   |\n" +
+		"|             |\n" +
+		"+-------------+\n"
+
+	assert.Equal(t, expected, Box("The quick brown fox", BoxOptions{Border: &BorderASCII, Title: "Map", Padding: 1, Width: 15}))
+}
+
+func TestBoxTitleFitsWidth(t *testing.T) {
+	// Without a width the box grows to fit the title
+	assert.Equal(t, "╭─ Journal ─╮", strings.Split(Box("hi", BoxOptions{Border: &BorderRounded, Title: "Journal", Padding: 1}), "\n")[0])
+
+	// With a width the title is cut short
+	assert.Equal(t, "╔═ Jour… ╗", strings.Split(Box("hi", BoxOptions{Border: &BorderDouble, Title: "Journal", Width: 10}), "\n")[0])
+}
+
+func TestBoxStyles(t *testing.T) {
+	result := Box(`<ansi fg="red">one two</ansi>`, BoxOptions{
+		Title:       `<ansi fg="yellow">T</ansi>`,
+		BorderStyle: "cyan",
+		Width:       9,
+	})
+
+	expected := "" +
+		`<ansi fg="cyan">┌─</ansi> <ansi fg="yellow">T</ansi> <ansi fg="cyan">───┐</ansi>` + "\n" +
+		`<ansi fg="cyan">│</ansi><ansi fg="red">one two</ansi><ansi fg="cyan">│</ansi>` + "\n" +
+		`<ansi fg="cyan">└───────┘</ansi>` + "\n"
+
+	assert.Equal(t, expected, result)
+}
+
+func TestBoxLinesLineUp(t *testing.T) {
+	content := `<ansi fg="yellow">A long quest description</ansi> that needs <ansi bg="blue">wrapping inside</ansi> the 日本語 frame`
+
+	for width := 6; width < 40; width++ {
+		result := Box(content, BoxOptions{Padding: 1, Width: width, BorderStyle: `fg="blue"`})
+		for _, line := range strings.Split(strings.TrimSuffix(result, "\n"), "\n") {
+			assert.Equal(t, width, VisibleWidth(line), "width=%d line=%q", width, line)
+		}
+	}
+}
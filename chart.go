@@ -0,0 +1,88 @@
+package ansitags
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PaletteChart returns a tagged string showing all 256 colors, each as a
+// background swatch followed by its index and hex value written in that
+// color. Pass a color code to draw the text on that background instead of
+// the terminal default, e.g. PaletteChart(0) to see how colors read on black.
+//
+// Usage:
+//
+// fmt.Println(ansitags.Parse(ansitags.PaletteChart()))
+func PaletteChart(background ...int) string {
+
+	bgAttr := ""
+	if len(background) > 0 && background[0] >= 0 && background[0] <= 255 {
+		bgAttr = ` bg="` + strconv.Itoa(background[0]) + `"`
+	}
+
+	var out strings.Builder
+
+	writeRow := func(from int, to int) {
+		for i := from; i <= to; i++ {
+			num := strconv.Itoa(i)
+			if i > from {
+				out.WriteString(" ")
+			}
+			out.WriteString(`<ansi bg="` + num + `">  </ansi><ansi fg="` + num + `"` + bgAttr + `>` +
+				fmt.Sprintf(" %3d #%s", i, ansi256[i].Hex) + `</ansi>`)
+		}
+		out.WriteString("\n")
+	}
+
+	// 16 system colors
+	writeRow(0, 7)
+	writeRow(8, 15)
+	out.WriteString("\n")
+
+	// 6x6x6 color cube
+	for i := 16; i < 232; i += 6 {
+		writeRow(i, i+5)
+	}
+	out.WriteString("\n")
+
+	// grayscale ramp
+	for i := 232; i < 256; i += 6 {
+		writeRow(i, i+5)
+	}
+
+	return out.String()
+}
+
+// AliasChart returns a tagged string listing every color alias currently
+// loaded, sorted by name, with its color code and a sample of the color as
+// both foreground and background.
+//
+// Usage:
+//
+// ansitags.LoadAliases("aliases.yaml")
+// fmt.Println(ansitags.Parse(ansitags.AliasChart()))
+func AliasChart() string {
+
+	aliases := loadAliasSnapshot()
+
+	names := make([]string, 0, len(aliases))
+	nameLen := 0
+	for name := range aliases {
+		names = append(names, name)
+		if len(name) > nameLen {
+			nameLen = len(name)
+		}
+	}
+	sort.Strings(names)
+
+	var out strings.Builder
+	for _, name := range names {
+		num := strconv.Itoa(aliases[name])
+		out.WriteString(fmt.Sprintf("%-*s %3s #%s ", nameLen, name, num, RGB(aliases[name]).Hex))
+		out.WriteString(`<ansi fg="` + num + `"> Sample </ansi> <ansi bg="` + num + `"> Sample </ansi>` + "\n")
+	}
+
+	return out.String()
+}
@@ -0,0 +1,41 @@
+package ansitags
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaletteChart(t *testing.T) {
+	chart := PaletteChart()
+
+	assert.Nil(t, Validate(chart))
+	assert.Equal(t, 256, strings.Count(chart, `<ansi bg=`))
+	assert.Contains(t, chart, `<ansi bg="207">  </ansi><ansi fg="207"> 207 #ff5fff</ansi>`)
+	assert.Equal(t, 2+1+36+1+4, strings.Count(chart, "\n"))
+}
+
+func TestPaletteChartOnBackground(t *testing.T) {
+	chart := PaletteChart(0)
+
+	assert.Nil(t, Validate(chart))
+	assert.Contains(t, chart, `<ansi fg="207" bg="0"> 207 #ff5fff</ansi>`)
+}
+
+func TestAliasChart(t *testing.T) {
+	assert.NoError(t, SetAlias("chart-test", 207))
+
+	chart := AliasChart()
+
+	assert.Nil(t, Validate(chart))
+	found := false
+	for _, line := range strings.Split(chart, "\n") {
+		if strings.HasPrefix(line, "chart-test ") {
+			found = true
+			assert.True(t, strings.HasSuffix(line, ` 207 #ff5fff <ansi fg="207"> Sample </ansi> <ansi bg="207"> Sample </ansi>`), line)
+		}
+	}
+	assert.True(t, found, "chart-test line missing")
+	assert.Equal(t, len(GetAliases()), strings.Count(chart, "\n"))
+}
@@ -0,0 +1,193 @@
+package ansitags
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// colorRule is a pattern and the opening tag put around its matches.
+type colorRule struct {
+	pattern Matcher
+	open    string
+}
+
+// Colorizer adds tags around text matching a list of rules, such as every
+// mention of a player name, a direction or an amount of gold. Rules are
+// tried in the order they were added and where two rules match overlapping
+// text the earlier rule wins.
+//
+// Only text outside of any tag is colored: text that is already tagged is
+// left alone, and matches never span or land inside a tag.
+//
+// Usage:
+//
+//	c := ansitags.NewColorizer().
+//		AddWords("cyan", "north", "south", "east", "west").
+//		AddRule(regexp.MustCompile(`\d+ gold`), "yellow")
+//	fmt.Println(ansitags.Parse(c.Colorize(message)))
+type Colorizer struct {
+	rules []colorRule
+}
+
+// NewColorizer returns a Colorizer with no rules.
+func NewColorizer() *Colorizer {
+	return &Colorizer{}
+}
+
+// AddRule colors matches of pattern with style, which is either tag
+// attributes such as `fg="red" bg="black"` or a bare alias for the
+// foreground. Use Literal for plain text or a *regexp.Regexp.
+func (c *Colorizer) AddRule(pattern Matcher, style string) *Colorizer {
+	if open := styleTag(style); open != "" {
+		c.rules = append(c.rules, colorRule{pattern: pattern, open: open})
+	}
+	return c
+}
+
+// AddWords colors each of words with style wherever it appears as a whole
+// word, that is without a letter, digit or underscore right before or after
+// it. A word that starts or ends with some other character, such as "$100",
+// isn't checked on that side. Longer words are preferred where one word
+// starts another.
+func (c *Colorizer) AddWords(style string, words ...string) *Colorizer {
+
+	var matcher wordsMatcher
+	for _, word := range words {
+		if word != "" {
+			matcher = append(matcher, word)
+		}
+	}
+	if len(matcher) == 0 {
+		return c
+	}
+
+	sort.SliceStable(matcher, func(i, j int) bool {
+		return len(matcher[i]) > len(matcher[j])
+	})
+
+	return c.AddRule(matcher, style)
+}
+
+// wordsMatcher finds whole word occurrences of its words, longest first.
+type wordsMatcher []string
+
+func (w wordsMatcher) FindAllStringIndex(s string, n int) [][]int {
+
+	var matches [][]int
+	for i := 0; i < len(s) && (n < 0 || len(matches) < n); {
+		end := -1
+		for _, word := range w {
+			if strings.HasPrefix(s[i:], word) && w.bounded(s, i, i+len(word)) {
+				end = i + len(word)
+				break
+			}
+		}
+		if end < 0 {
+			_, size := utf8.DecodeRuneInString(s[i:])
+			i += size
+			continue
+		}
+		matches = append(matches, []int{i, end})
+		i = end
+	}
+
+	return matches
+}
+
+// bounded reports whether s[start:end] isn't joined to a word character on
+// either side. Sides where the match itself ends in a non-word character
+// aren't checked.
+func (w wordsMatcher) bounded(s string, start int, end int) bool {
+
+	first, _ := utf8.DecodeRuneInString(s[start:end])
+	if before, _ := utf8.DecodeLastRuneInString(s[:start]); start > 0 && isWordRune(first) && isWordRune(before) {
+		return false
+	}
+
+	last, _ := utf8.DecodeLastRuneInString(s[start:end])
+	if after, _ := utf8.DecodeRuneInString(s[end:]); end < len(s) && isWordRune(last) && isWordRune(after) {
+		return false
+	}
+
+	return true
+}
+
+// isWordRune reports whether r is part of a word. Combining marks count, so
+// that a decomposed "ë" isn't split from its base letter.
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// Colorize returns str with the rules applied.
+func (c *Colorizer) Colorize(str string) string {
+
+	if len(c.rules) == 0 {
+		return str
+	}
+
+	var out strings.Builder
+	out.Grow(len(str))
+
+	depth := 0
+	for _, tok := range tokenize(str) {
+		switch tok.kind {
+		case tokenOpen:
+			depth++
+			out.WriteString(tok.raw)
+		case tokenClose:
+			if depth > 0 {
+				depth--
+			}
+			out.WriteString(tok.raw)
+		default:
+			if depth > 0 {
+				out.WriteString(tok.raw)
+			} else {
+				c.colorizeText(&out, tok.raw)
+			}
+		}
+	}
+
+	return out.String()
+}
+
+// colorizeText writes untagged text to out with the rules applied.
+func (c *Colorizer) colorizeText(out *strings.Builder, text string) {
+
+	type colorSpan struct {
+		start int
+		end   int
+		open  string
+	}
+
+	var taken []colorSpan
+	for _, rule := range c.rules {
+	spans:
+		for _, span := range matchSpans(text, rule.pattern) {
+			for _, t := range taken {
+				if span[0] < t.end && t.start < span[1] {
+					continue spans
+				}
+			}
+			taken = append(taken, colorSpan{start: span[0], end: span[1], open: rule.open})
+		}
+	}
+
+	sort.Slice(taken, func(i, j int) bool {
+		return taken[i].start < taken[j].start
+	})
+
+	last := 0
+	for _, span := range taken {
+		// Parse doesn't start a tag right after a <, so that < is escaped
+		out.WriteString(text[last:span.start])
+		escapeTagStartBefore(out)
+		out.WriteString(span.open + text[span.start:span.end])
+		escapeTagStartBefore(out)
+		out.WriteString("</ansi>")
+		last = span.end
+	}
+	out.WriteString(text[last:])
+}
@@ -0,0 +1,81 @@
+package ansitags
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorizerWords(t *testing.T) {
+	c := NewColorizer().AddWords("cyan", "north", "east", "northeast")
+
+	assert.Equal(t, `Exits: <ansi fg="cyan">north</ansi>, <ansi fg="cyan">northeast</ansi> and <ansi fg="cyan">east</ansi>.`,
+		c.Colorize("Exits: north, northeast and east."))
+
+	// Only whole words
+	assert.Equal(t, "The feast is northward.", c.Colorize("The feast is northward."))
+}
+
+func TestColorizerWordsUnicode(t *testing.T) {
+	c := NewColorizer().AddWords("cyan", "Zoë", "$100")
+
+	assert.Equal(t, `<ansi fg="cyan">Zoë</ansi> paid <ansi fg="cyan">$100</ansi>.`, c.Colorize("Zoë paid $100."))
+	assert.Equal(t, `Zoëlle paid $1000.`, c.Colorize(`Zoëlle paid $1000.`))
+	assert.Equal(t, `(<ansi fg="cyan">$100</ansi>)`, c.Colorize(`($100)`))
+
+	// The letters around a word aren't only ASCII ones
+	c = NewColorizer().AddWords("cyan", "north")
+	assert.Equal(t, "northé éncnorth", c.Colorize("northé éncnorth"))
+	assert.Equal(t, "north\u0301", c.Colorize("north\u0301"))
+}
+
+func TestColorizerAfterTagStart(t *testing.T) {
+	c := NewColorizer().AddWords("cyan", "Bob")
+
+	result := c.Colorize("<Bob> hi")
+	assert.Equal(t, `<\<ansi fg="cyan">Bob</ansi>> hi`, result)
+	assert.Equal(t, "<\x1b[38;5;6m\x1b[49mBob\x1b[0m> hi", Parse(result))
+
+	result = NewColorizer().AddRule(Literal("a<"), "red").Colorize("a<b")
+	assert.Equal(t, `<ansi fg="red">a<\</ansi>b`, result)
+}
+
+func TestColorizerRules(t *testing.T) {
+	c := NewColorizer().
+		AddRule(regexp.MustCompile(`\d+ gold`), `fg="yellow"`).
+		AddRule(Literal("Bob"), "red")
+
+	assert.Equal(t, `<ansi fg="red">Bob</ansi> gives you <ansi fg="yellow">25 gold</ansi>.`, c.Colorize("Bob gives you 25 gold."))
+}
+
+func TestColorizerRuleOrder(t *testing.T) {
+	// Earlier rules win where matches overlap
+	c := NewColorizer().
+		AddRule(Literal("red dragon"), "red").
+		AddWords("green", "dragon")
+
+	assert.Equal(t, `A <ansi fg="red">red dragon</ansi> and a <ansi fg="green">dragon</ansi>.`, c.Colorize("A red dragon and a dragon."))
+}
+
+func TestColorizerSkipsTags(t *testing.T) {
+	c := NewColorizer().AddWords("cyan", "Bob", "blue")
+
+	// Already tagged text and attribute values are left alone
+	input := `<ansi fg="blue">Bob</ansi> and Bob`
+	assert.Equal(t, `<ansi fg="blue">Bob</ansi> and <ansi fg="cyan">Bob</ansi>`, c.Colorize(input))
+
+	// Matches don't cross tags
+	c = NewColorizer().AddRule(Literal("ab"), "red")
+	assert.Equal(t, `a<ansi fg="green"></ansi>b`, c.Colorize(`a<ansi fg="green"></ansi>b`))
+}
+
+func TestColorizerAliases(t *testing.T) {
+	c := NewColorizer().AddWords("yellow", "gold")
+	assert.Equal(t, Parse(`<ansi fg="yellow">gold</ansi>`), Parse(c.Colorize("gold")))
+}
+
+func TestColorizerEmpty(t *testing.T) {
+	assert.Equal(t, "plain", NewColorizer().Colorize("plain"))
+	assert.Equal(t, "plain", NewColorizer().AddWords("red").AddRule(Literal("plain"), "").Colorize("plain"))
+}
@@ -0,0 +1,81 @@
+package ansitags
+
+import "strings"
+
+// columnGap is the number of spaces between columns laid out by Columns.
+const columnGap = 2
+
+// Columns lays out tagged items in as many columns as fit in totalWidth
+// visible columns, filling each column top to bottom before moving on to
+// the next, like ls does. Each column is as wide as its widest item. If even
+// a single column doesn't fit, every item gets a line of its own.
+//
+// Usage:
+//
+//	for _, line := range ansitags.Columns(spellNames, 80) {
+//		fmt.Println(ansitags.Parse(line))
+//	}
+func Columns(items []string, totalWidth int) []string {
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	widths := make([]int, len(items))
+	for i, item := range items {
+		widths[i] = VisibleWidth(item)
+	}
+
+	rows := len(items)
+	var colWidths []int
+
+	// Try the fewest rows first, which gives the most columns
+	for tryRows := 1; tryRows <= len(items); tryRows++ {
+		cols := (len(items) + tryRows - 1) / tryRows
+		if (cols-1)*tryRows >= len(items) {
+			// Would leave the last column empty
+			continue
+		}
+
+		tryWidths := make([]int, cols)
+		total := columnGap * (cols - 1)
+		for i, w := range widths {
+			col := i / tryRows
+			if w > tryWidths[col] {
+				total += w - tryWidths[col]
+				tryWidths[col] = w
+			}
+		}
+
+		if total <= totalWidth || cols == 1 {
+			rows = tryRows
+			colWidths = tryWidths
+			break
+		}
+	}
+
+	gap := strings.Repeat(" ", columnGap)
+
+	lines := make([]string, rows)
+	for row := range lines {
+		var line strings.Builder
+		for col := range colWidths {
+			i := col*rows + row
+			if i >= len(items) {
+				break
+			}
+			if col > 0 {
+				line.WriteString(gap)
+			}
+			last := col == len(colWidths)-1 || i+rows >= len(items)
+			if last {
+				line.WriteString(items[i])
+			} else {
+				line.WriteString(PadRight(items[i], colWidths[col], ' '))
+			}
+		}
+		lines[row] = line.String()
+	}
+
+	return lines
+}
@@ -0,0 +1,50 @@
+package ansitags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumns(t *testing.T) {
+	items := []string{"north", "south", "east", "west", "up", "down", "northeast"}
+
+	assert.Equal(t, []string{
+		"north  west  northeast",
+		"south  up",
+		"east   down",
+	}, Columns(items, 24))
+
+	assert.Equal(t, []string{"north  south  east  west  up  down  northeast"}, Columns(items, 80))
+}
+
+func TestColumnsTags(t *testing.T) {
+	items := []string{`<ansi fg="red">fireball</ansi>`, "heal", `<ansi fg="blue">frost</ansi>`, "light"}
+
+	assert.Equal(t, []string{
+		`<ansi fg="red">fireball</ansi>  <ansi fg="blue">frost</ansi>`,
+		`heal      light`,
+	}, Columns(items, 16))
+}
+
+func TestColumnsSingleColumn(t *testing.T) {
+	items := []string{"a very long item", "another long one"}
+	assert.Equal(t, items, Columns(items, 5))
+}
+
+func TestColumnsFit(t *testing.T) {
+	items := []string{"a", "bb", "ccc", "dddd", "eeeee", "ffffff", "日本語", "g", "hh", "iii"}
+
+	for width := 1; width <= 60; width++ {
+		for _, line := range Columns(items, width) {
+			if VisibleWidth(line) > width {
+				// Only allowed when a single column can't fit
+				assert.Len(t, Columns(items, width), len(items), "width=%d", width)
+			}
+		}
+	}
+}
+
+func TestColumnsEmpty(t *testing.T) {
+	assert.Nil(t, Columns(nil, 80))
+}
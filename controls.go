@@ -0,0 +1,231 @@
+package ansitags
+
+import "io"
+
+type controlState uint8
+
+const (
+	controlText        controlState = iota
+	controlEsc                      // after ESC
+	controlEscIntermed              // ESC followed by intermediate bytes, e.g. ESC ( B
+	controlCSI                      // ESC [ or C1 CSI, up to the final byte
+	controlString                   // OSC, DCS, SOS, PM or APC, up to BEL or ST
+	controlStringEsc                // ESC inside a string, which may be the start of ST
+
+	c1Lead byte = 0xC2 // first byte of the UTF-8 encoding of U+0080–U+009F
+)
+
+// controlFilter removes escape sequences and control characters from text
+// one byte at a time, so it works the same on strings and streams. Sequences
+// started by ESC or by a C1 control are removed along with every C1 control.
+// With all set, C0 controls and DEL are removed as well, keeping only
+// newlines, tabs and \r\n line endings.
+type controlFilter struct {
+	state   controlState
+	pending byte // c1Lead or '\r', held until the next byte shows what it is
+	all     bool
+}
+
+// write feeds b through the filter and appends whatever is kept to out.
+func (f *controlFilter) write(out []byte, b byte) []byte {
+
+	switch f.pending {
+	case c1Lead:
+		f.pending = 0
+		if b >= 0x80 && b <= 0x9F {
+			return f.c1(out, b)
+		}
+		out = f.step(out, c1Lead)
+	case '\r':
+		f.pending = 0
+		if b == '\n' {
+			return append(out, '\r', '\n')
+		}
+	}
+
+	if b == c1Lead {
+		f.pending = b
+		return out
+	}
+
+	return f.step(out, b)
+}
+
+// flush appends anything still held back at the end of the input and resets
+// the filter. An unfinished sequence is dropped.
+func (f *controlFilter) flush(out []byte) []byte {
+	if f.pending == c1Lead && f.state == controlText {
+		out = append(out, c1Lead)
+	}
+	f.pending = 0
+	f.state = controlText
+	return out
+}
+
+// step handles one byte that isn't part of a C1 control.
+func (f *controlFilter) step(out []byte, b byte) []byte {
+
+	switch f.state {
+	case controlText:
+		switch {
+		case b == escByte:
+			f.state = controlEsc
+		case !f.all || b == '\n' || b == '\t' || (b >= 0x20 && b != 0x7F):
+			out = append(out, b)
+		case b == '\r':
+			f.pending = b
+		}
+
+	case controlEsc:
+		switch {
+		case b == '[':
+			f.state = controlCSI
+		case b == ']' || b == 'P' || b == 'X' || b == '^' || b == '_':
+			f.state = controlString
+		case b >= 0x20 && b <= 0x2F:
+			f.state = controlEscIntermed
+		case b >= 0x30 && b <= 0x7E:
+			f.state = controlText
+		default:
+			f.state = controlText
+			return f.step(out, b)
+		}
+
+	case controlEscIntermed:
+		switch {
+		case b >= 0x20 && b <= 0x2F:
+		case b >= 0x30 && b <= 0x7E:
+			f.state = controlText
+		default:
+			f.state = controlText
+			return f.step(out, b)
+		}
+
+	case controlCSI:
+		switch {
+		case b >= 0x20 && b <= 0x3F:
+		case b >= 0x40 && b <= 0x7E:
+			f.state = controlText
+		default:
+			f.state = controlText
+			return f.step(out, b)
+		}
+
+	case controlString:
+		switch b {
+		case 0x07:
+			f.state = controlText
+		case escByte:
+			f.state = controlStringEsc
+		case '\n':
+			// Don't let an unterminated string swallow the rest of the input
+			f.state = controlText
+			return f.step(out, b)
+		}
+
+	case controlStringEsc:
+		if b == '\\' {
+			f.state = controlText
+			return out
+		}
+		f.state = controlEsc
+		return f.step(out, b)
+	}
+
+	return out
+}
+
+// c1 handles the C1 control U+0080+(c-0x80), which is always removed.
+func (f *controlFilter) c1(out []byte, c byte) []byte {
+
+	if f.state == controlString || f.state == controlStringEsc {
+		if c == 0x9C { // ST
+			f.state = controlText
+		}
+		return out
+	}
+
+	switch c {
+	case 0x9B: // CSI
+		f.state = controlCSI
+	case 0x90, 0x98, 0x9D, 0x9E, 0x9F: // DCS, SOS, OSC, PM, APC
+		f.state = controlString
+	default:
+		f.state = controlText
+	}
+
+	return out
+}
+
+// filterControls runs str through a controlFilter.
+func filterControls(str string, all bool) string {
+
+	i := 0
+	for ; i < len(str); i++ {
+		if b := str[i]; b < 0x20 || b == 0x7F || b == c1Lead {
+			break
+		}
+	}
+	if i == len(str) {
+		return str
+	}
+
+	f := controlFilter{all: all}
+	out := make([]byte, i, len(str))
+	copy(out, str[:i])
+
+	for ; i < len(str); i++ {
+		out = f.write(out, str[i])
+	}
+
+	return string(f.flush(out))
+}
+
+// StripANSI removes raw escape sequences from text: CSI sequences such as
+// colors and cursor movement, OSC sequences such as window titles, the other
+// string sequences and two byte ESC sequences, as well as C1 controls. Other
+// control characters are kept. An OSC or other string sequence missing its
+// terminator ends at the next newline.
+func StripANSI(str string) string {
+	return filterControls(str, false)
+}
+
+// SanitizeControls removes everything StripANSI does, plus every other
+// control character (bell, backspace, DEL, lone carriage returns and so on)
+// except newlines, tabs and \r\n line endings. Parsed with tags, the result
+// only holds the escape codes that come from the tags.
+//
+// Usage:
+//
+// say := ansitags.SanitizeControls(playerInput)
+func SanitizeControls(str string) string {
+	return filterControls(str, true)
+}
+
+// textFilter runs the text between tags through a controlFilter as the
+// parser writes it, for the StripControls behavior. Tags are matched on the
+// input as it was, so removing controls can't put a tag together.
+type textFilter struct {
+	filter controlFilter
+	buf    []byte
+}
+
+// write filters text and writes what is kept to out.
+func (t *textFilter) write(out io.Writer, text ...byte) {
+	t.buf = t.buf[:0]
+	for _, b := range text {
+		t.buf = t.filter.write(t.buf, b)
+	}
+	if len(t.buf) > 0 {
+		out.Write(t.buf)
+	}
+}
+
+// flush ends the text before a tag or at the end of the input, so that an
+// escape sequence can't carry on past it.
+func (t *textFilter) flush(out io.Writer) {
+	t.buf = t.filter.flush(t.buf[:0])
+	if len(t.buf) > 0 {
+		out.Write(t.buf)
+	}
+}
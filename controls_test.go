@@ -0,0 +1,91 @@
+package ansitags
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripANSI(t *testing.T) {
+	assert.Equal(t, "plain", StripANSI("plain"))
+	assert.Equal(t, "red text", StripANSI("\x1b[31mred\x1b[0m text"))
+	assert.Equal(t, "ab", StripANSI("a\x1b[1;1H\x1b[?25lb"))
+	assert.Equal(t, "title", StripANSI("\x1b]0;x\x07title"))
+	assert.Equal(t, "dcs", StripANSI("\x1bPq#0;2;0;0;0\x1b\\dcs"))
+	assert.Equal(t, "keypad", StripANSI("\x1b=keypad"))
+
+	// Other controls are left alone
+	assert.Equal(t, "a\x07b\r\n", StripANSI("a\x07b\x1b[K\r\n"))
+}
+
+func TestStripANSIBrokenSequences(t *testing.T) {
+	// A sequence cut short by a control or another ESC ends there
+	assert.Equal(t, "a\nb", StripANSI("a\x1b[3\nb"))
+	assert.Equal(t, "ab", StripANSI("a\x1b[3\x1b[4mb"))
+	assert.Equal(t, "a", StripANSI("a\x1b["))
+	assert.Equal(t, "a", StripANSI("a\x1b"))
+	assert.Equal(t, "aé", StripANSI("a\x1b]0;title\x1b[1mé"))
+}
+
+func TestSanitizeControls(t *testing.T) {
+	assert.Equal(t, "plain text", SanitizeControls("plain text"))
+	assert.Equal(t, "line\r\nnext\n\ttab", SanitizeControls("line\r\nnext\n\ttab"))
+	assert.Equal(t, "abcd", SanitizeControls("a\rb\x08c\x00\x7fd\r"))
+	assert.Equal(t, "safe", SanitizeControls("\x1b[2J\x1b[3Jsafe\x07"))
+	assert.Equal(t, "¡Hola! é", SanitizeControls("¡Hola!\u0085 é"))
+}
+
+func TestSanitizeControlsPlainWithStripTags(t *testing.T) {
+	input := "<ansi fg=red>\x1b[31mred\x07</ansi> \u009b31mtext"
+	assert.Equal(t, "red text", Parse(input, StripTags, StripControls))
+}
+
+func TestParseStreamingStripControlsOneByteAtATime(t *testing.T) {
+	input := "a\x1b[31mb\u009b2Jc\r\nd\x1b]0;t\x1b\\e\xc2"
+
+	var out strings.Builder
+	writer := bufio.NewWriter(&out)
+	ParseStreaming(bufio.NewReader(iotest.OneByteReader(strings.NewReader(input))), writer, StripControls)
+	writer.Flush()
+
+	assert.Equal(t, SanitizeControls(input), out.String())
+	assert.Equal(t, "abc\r\nde\xc2", out.String())
+}
+
+func TestStripControlsCantFormTags(t *testing.T) {
+	// Removing the controls from these would leave a tag behind
+	for _, input := range []string{
+		"<\x1b[0mansi clear=all>x",
+		"<\x07ansi clear=scrollback>x",
+		"<\u009b2J/ansi>x",
+	} {
+		output := Parse(input, StripControls)
+		assert.NotContains(t, output, "\x1b", input)
+
+		var out strings.Builder
+		writer := bufio.NewWriter(&out)
+		ParseStreaming(bufio.NewReader(strings.NewReader(input)), writer, StripControls)
+		writer.Flush()
+		assert.Equal(t, output, out.String(), input)
+	}
+
+	assert.Equal(t, "<ansi clear=all>x", Parse("<\x1b[0mansi clear=all>x", StripControls))
+}
+
+func TestStripControlsAfterSanitize(t *testing.T) {
+	input := Sanitize("<\x07ansi clear=scrollback>hi", PolicyColors)
+	assert.Equal(t, "<ansi clear=scrollback>hi", Parse(input, StripControls))
+}
+
+func TestParseStreamingStripControls(t *testing.T) {
+	var out strings.Builder
+	writer := bufio.NewWriter(&out)
+
+	ParseStreaming(bufio.NewReader(iotest.HalfReader(strings.NewReader("<ansi fg=red>\x1b[2Jhi</ansi>"))), writer, StripControls, StripTags)
+	writer.Flush()
+
+	assert.Equal(t, "hi", out.String())
+}
@@ -0,0 +1,65 @@
+package ansitags
+
+import "strings"
+
+// escapedTagStart is how a literal tagStart is written in tagged text.
+var escapedTagStart = string([]byte{tagStart, tagEscape})
+
+// Escape makes text inert, so that Parse prints it as is rather than reading
+// any tags in it. Every < is written as <\, which Parse turns back into a
+// plain < and which never starts a tag. The escaped text can be placed
+// anywhere in a tagged string, including right before a tag.
+//
+// Usage:
+//
+// help := "Color text with " + ansitags.Escape(`<ansi fg="red">red</ansi>`)
+// fmt.Println(ansitags.Parse(help)) // Color text with <ansi fg="red">red</ansi>
+func Escape(text string) string {
+	if strings.IndexByte(text, tagStart) < 0 {
+		return text
+	}
+	return strings.ReplaceAll(text, string(tagStart), escapedTagStart)
+}
+
+// unescape returns the text between tags as it is printed, with each escaped
+// tagStart turned back into a plain one.
+func unescape(text string) string {
+	if !strings.Contains(text, escapedTagStart) {
+		return text
+	}
+	return strings.ReplaceAll(text, escapedTagStart, string(tagStart))
+}
+
+// unescapeOffsets is unescape, also returning the offset in text of every
+// byte of the result, plus len(text) at the end. The offsets are nil if text
+// has nothing escaped in it.
+func unescapeOffsets(text string) (string, []int) {
+
+	if !strings.Contains(text, escapedTagStart) {
+		return text, nil
+	}
+
+	var plain strings.Builder
+	plain.Grow(len(text))
+	offsets := make([]int, 0, len(text)+1)
+
+	for i := 0; i < len(text); i++ {
+		offsets = append(offsets, i)
+		plain.WriteByte(text[i])
+		if text[i] == tagStart && i+1 < len(text) && text[i+1] == tagEscape {
+			i++
+		}
+	}
+
+	return plain.String(), append(offsets, len(text))
+}
+
+// escapeTagStartBefore escapes a tagStart that out ends with, if any, before
+// something is written after it. Parse never starts a tag right after a
+// tagStart, so a tag written there would be printed as text, and text
+// written there could join up with it into a tag.
+func escapeTagStartBefore(out *strings.Builder) {
+	if written := out.String(); len(written) > 0 && written[len(written)-1] == tagStart {
+		out.WriteByte(tagEscape)
+	}
+}
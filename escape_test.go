@@ -0,0 +1,47 @@
+package ansitags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, "plain text", Escape("plain text"))
+	assert.Equal(t, `<\ansi fg="red">red<\/ansi>`, Escape(`<ansi fg="red">red</ansi>`))
+	assert.Equal(t, `<\\`, Escape(`<\`))
+}
+
+func TestEscapeParsesToItself(t *testing.T) {
+	inputs := []string{
+		`<ansi fg="red">red</ansi>`,
+		`<ansi clear=all>`,
+		`<<ansi>>`,
+		`<\ <\\ \<`,
+		`trailing <`,
+		`trailing \`,
+	}
+
+	for _, input := range inputs {
+		assert.Equal(t, input, Parse(Escape(input)), input)
+		assert.Equal(t, input, Parse(Escape(input), StripTags), input)
+		assert.Equal(t, input, Parse(Escape(input), HTML), input)
+		assert.Equal(t, plainWidth(input, 0), VisibleWidth(Escape(input)), input)
+	}
+}
+
+func TestEscapeNextToTags(t *testing.T) {
+	result := Parse(`<ansi fg="red">` + Escape(`x <`) + `</ansi>` + Escape(`\`) + `<ansi fg="red">y</ansi>`)
+	assert.Equal(t, "\x1b[38;5;1m\x1b[49mx <\x1b[0m\\\x1b[38;5;1m\x1b[49my\x1b[0m", result)
+}
+
+func TestUnescapeOffsets(t *testing.T) {
+	plain, offsets := unescapeOffsets("abc")
+	assert.Equal(t, "abc", plain)
+	assert.Nil(t, offsets)
+
+	plain, offsets = unescapeOffsets(`a<\b<\\`)
+	assert.Equal(t, `a<b<\`, plain)
+	assert.Equal(t, []int{0, 1, 3, 4, 6, 7}, offsets)
+	assert.Equal(t, plain, unescape(`a<\b<\\`))
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/GoMudEngine/ansitags"
+)
+
+// stringList collects the values of a repeatable flag.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// commonFlags are the flags shared by the commands, see newFlagSet.
+type commonFlags struct {
+	aliases       stringList
+	colorProfile  string
+	format        string
+	stripControls bool
+}
+
+// newFlagSet returns a flag set with --aliases, plus --format and
+// --strip-controls if withFormat is set and --color-profile if withColor is.
+func newFlagSet(name string, withFormat bool, withColor bool) (*flag.FlagSet, *commonFlags) {
+
+	common := &commonFlags{}
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Var(&common.aliases, "aliases", "alias yaml file to load (repeatable)")
+	if withColor {
+		flags.StringVar(&common.colorProfile, "color-profile", "256", "color output: 256, 16, truecolor or mono")
+	}
+	if withFormat {
+		flags.StringVar(&common.format, "format", "ansi", "output format: ansi, html, strip or mono")
+		flags.BoolVar(&common.stripControls, "strip-controls", false, "remove raw escape codes and control characters from the input")
+	}
+
+	return flags, common
+}
+
+// setup loads any alias files and returns the parse behaviors selected by
+// the --format and --color-profile flags.
+func (c *commonFlags) setup() ([]ansitags.ParseBehavior, error) {
+
+	if len(c.aliases) > 0 {
+		if err := ansitags.LoadAliases(c.aliases...); err != nil {
+			return nil, err
+		}
+	}
+
+	var behaviors []ansitags.ParseBehavior
+
+	switch c.format {
+	case "", "ansi":
+	case "html":
+		behaviors = append(behaviors, ansitags.HTML)
+	case "strip":
+		behaviors = append(behaviors, ansitags.StripTags)
+	case "mono":
+		behaviors = append(behaviors, ansitags.Monochrome)
+	default:
+		return nil, fmt.Errorf("unknown format %q", c.format)
+	}
+
+	if c.stripControls {
+		behaviors = append(behaviors, ansitags.StripControls)
+	}
+
+	switch c.colorProfile {
+	case "", "256":
+	case "16":
+		behaviors = append(behaviors, ansitags.Color16)
+	case "truecolor", "24bit":
+		behaviors = append(behaviors, ansitags.TrueColor)
+	case "mono", "none":
+		behaviors = append(behaviors, ansitags.Monochrome)
+	default:
+		return nil, fmt.Errorf("unknown color profile %q", c.colorProfile)
+	}
+
+	return behaviors, nil
+}
+
+// eachInput calls fn with a reader for every named file, or stdin if there
+// are none.
+func eachInput(files []string, fn func(name string, r io.Reader) error) error {
+
+	if len(files) == 0 {
+		return fn("stdin", os.Stdin)
+	}
+
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = fn(name, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func fail(err error) int {
+	fmt.Fprintln(os.Stderr, err)
+	return exitError
+}
+
+func runRender(args []string) int {
+
+	flags, common := newFlagSet("render", true, true)
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	behaviors, err := common.setup()
+	if err != nil {
+		return fail(err)
+	}
+
+	output := bufio.NewWriter(os.Stdout)
+
+	err = eachInput(flags.Args(), func(name string, r io.Reader) error {
+		ansitags.ParseStreaming(bufio.NewReader(r), output, behaviors...)
+		return nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	return exitOK
+}
+
+func runValidate(args []string) int {
+
+	flags, common := newFlagSet("validate", false, false)
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if _, err := common.setup(); err != nil {
+		return fail(err)
+	}
+
+	result := exitOK
+
+	err := eachInput(flags.Args(), func(name string, r io.Reader) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		str := string(data)
+		for _, problem := range ansitags.Validate(str) {
+			line := strings.Count(str[:problem.Offset], "\n") + 1
+			col := problem.Offset - strings.LastIndex(str[:problem.Offset], "\n")
+			fmt.Printf("%s:%d:%d: %s: %s\n", name, line, col, problem.Message, problem.Tag)
+			result = exitInvalid
+		}
+		return nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	return result
+}
+
+func runFromANSI(args []string) int {
+
+	// The output only uses color numbers, so aliases and color profiles
+	// don't apply here
+	flags := flag.NewFlagSet("from-ansi", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	err := eachInput(flags.Args(), func(name string, r io.Reader) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		fmt.Print(ansitags.FromANSI(string(data)))
+		return nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	return exitOK
+}
+
+func runWrap(args []string) int {
+
+	flags, common := newFlagSet("wrap", true, true)
+	width := flags.Int("width", 80, "maximum visible line width")
+	preserveSpaces := flags.Bool("preserve-spaces", false, "keep runs of spaces instead of collapsing them")
+	indent := flags.Int("indent", 0, "spaces before the first line of each paragraph")
+	hanging := flags.Int("hanging", 0, "spaces before every other line of each paragraph")
+	prefix := flags.String("prefix", "", "tagged text written at the start of every line")
+	justify := flags.Bool("justify", false, "widen spaces so lines fill the width")
+	longWords := flags.String("long-words", "break", "words wider than the line: break, hyphenate or overflow")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	opts := ansitags.WrapOptions{
+		PreserveSpaces: *preserveSpaces,
+		FirstIndent:    *indent,
+		HangingIndent:  *hanging,
+		Prefix:         *prefix,
+		Justify:        *justify,
+	}
+
+	switch *longWords {
+	case "break":
+		opts.LongWords = ansitags.LongWordBreak
+	case "hyphenate":
+		opts.LongWords = ansitags.LongWordHyphenate
+	case "overflow":
+		opts.LongWords = ansitags.LongWordOverflow
+	default:
+		return fail(fmt.Errorf("unknown long word mode %q", *longWords))
+	}
+
+	behaviors, err := common.setup()
+	if err != nil {
+		return fail(err)
+	}
+
+	err = eachInput(flags.Args(), func(name string, r io.Reader) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		for _, line := range ansitags.Wrap(strings.TrimSuffix(string(data), "\n"), *width, opts) {
+			fmt.Println(ansitags.Parse(line, behaviors...))
+		}
+		return nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	return exitOK
+}
+
+func runPalette(args []string) int {
+
+	flags, common := newFlagSet("palette", false, true)
+	background := flags.Int("bg", -1, "draw the color samples on this background color")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	behaviors, err := common.setup()
+	if err != nil {
+		return fail(err)
+	}
+
+	fmt.Print(ansitags.Parse(ansitags.PaletteChart(*background), behaviors...))
+
+	return exitOK
+}
+
+func runAliases(args []string) int {
+
+	flags, common := newFlagSet("aliases", false, true)
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	behaviors, err := common.setup()
+	if err != nil {
+		return fail(err)
+	}
+
+	fmt.Print(ansitags.Parse(ansitags.AliasChart(), behaviors...))
+
+	return exitOK
+}
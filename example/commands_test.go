@@ -0,0 +1,128 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// runCaptured runs a command and returns what it wrote to stdout and its
+// exit code.
+func runCaptured(t *testing.T, run func(args []string) int, args ...string) (string, int) {
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	code := run(args)
+	os.Stdout = stdout
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(out), code
+}
+
+// writeFile writes content to a file in a temporary directory and returns
+// its path.
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRender(t *testing.T) {
+	file := writeFile(t, "help.txt", `<ansi fg="red">hi</ansi>`)
+
+	out, code := runCaptured(t, runRender, file)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "\x1b[38;5;1m\x1b[49mhi\x1b[0m", out)
+
+	out, code = runCaptured(t, runRender, "--format", "strip", file)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "hi", out)
+
+	out, code = runCaptured(t, runRender, "--color-profile", "16", file)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "\x1b[31m\x1b[49mhi\x1b[0m", out)
+
+	_, code = runCaptured(t, runRender, "--format", "bogus", file)
+	assert.Equal(t, exitError, code)
+}
+
+func TestRenderStripControls(t *testing.T) {
+	file := writeFile(t, "log.txt", "<\x1b[0mansi clear=all>x\x07")
+
+	out, code := runCaptured(t, runRender, "--strip-controls", file)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "<ansi clear=all>x", out)
+}
+
+func TestValidate(t *testing.T) {
+	good := writeFile(t, "good.txt", `<ansi fg="red">hi</ansi>`)
+	bad := writeFile(t, "bad.txt", "line one\n<ansi fg=\"nocolor\">hi")
+
+	out, code := runCaptured(t, runValidate, good)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "", out)
+
+	out, code = runCaptured(t, runValidate, good, bad)
+	assert.Equal(t, exitInvalid, code)
+	assert.Contains(t, out, bad+":2:1: ")
+
+	_, code = runCaptured(t, runValidate, filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Equal(t, exitError, code)
+}
+
+func TestValidateFlags(t *testing.T) {
+	file := writeFile(t, "good.txt", `<ansi fg="red">hi</ansi>`)
+
+	// Validating doesn't write colors, so there's no profile to choose
+	_, code := runCaptured(t, runValidate, "--color-profile", "16", file)
+	assert.Equal(t, exitError, code)
+
+	_, code = runCaptured(t, runValidate, "--aliases", filepath.Join(t.TempDir(), "missing.yaml"), file)
+	assert.Equal(t, exitError, code)
+}
+
+func TestFromANSI(t *testing.T) {
+	file := writeFile(t, "captured.log", "\x1b[31mred\x1b[0m text")
+
+	out, code := runCaptured(t, runFromANSI, file)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, `<ansi fg="1">red</ansi> text`, out)
+
+	_, code = runCaptured(t, runFromANSI, "--aliases", "aliases.yaml", file)
+	assert.Equal(t, exitError, code)
+}
+
+func TestWrap(t *testing.T) {
+	file := writeFile(t, "motd.txt", "one two three four\n")
+
+	out, code := runCaptured(t, runWrap, "--width", "9", "--format", "strip", file)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "one two\nthree\nfour\n", out)
+
+	_, code = runCaptured(t, runWrap, "--long-words", "bogus", file)
+	assert.Equal(t, exitError, code)
+}
+
+func TestPaletteAndAliases(t *testing.T) {
+	out, code := runCaptured(t, runPalette, "--color-profile", "mono")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, out, "255")
+
+	_, code = runCaptured(t, runAliases, "--color-profile", "bogus")
+	assert.Equal(t, exitError, code)
+}
@@ -5,44 +5,99 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/GoMudEngine/ansitags"
 )
 
-// accepts a string via shell pipe and processes with ansitags.Parse()
+const (
+	exitOK      = 0
+	exitInvalid = 1 // validation found problems
+	exitError   = 2 // bad usage or unreadable input
+)
+
+type command struct {
+	args string
+	help string
+	run  func(args []string) int
+}
+
+var commands = map[string]command{
+	"render":    {"[--format ansi|html|strip|mono] [file ...]", "parse tagged text", runRender},
+	"validate":  {"[file ...]", "check tags, exit 1 on problems", runValidate},
+	"from-ansi": {"[file ...]", "convert ANSI escape codes to tags", runFromANSI},
+	"wrap":      {"--width N [--indent N] [--prefix str] [...] [file ...]", "word wrap then parse tagged text", runWrap},
+	"palette":   {"[--bg N]", "show the 256 color palette", runPalette},
+	"aliases":   {"", "list color aliases with samples", runAliases},
+}
+
+// ansitags is a command line front end for the ansitags package.
+// Every command reads the named files, or stdin if none are given.
+//
+// Usage:
+//
+// ansitags render --aliases aliases.yaml help.txt
+// ansitags validate --aliases aliases.yaml *.txt
+// echo "<ansi fg=red>Bingo</ansi>" | ansitags
 func main() {
 
-	modePtr := flag.String("mode", "parse", "[parse|generate]")
-	flag.Parse()
+	if len(os.Args) < 2 || strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runLegacy(os.Args[1:]))
+	}
 
-	// Useful for testing... example:
-	// ./example/bin/ansitags -mode=generate | ./example/bin/ansitags
-	if *modePtr == "generate" {
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+		printUsage()
+		os.Exit(exitError)
+	}
+
+	os.Exit(cmd.run(os.Args[2:]))
+}
 
-		str := "Some normal text <ansi fg=black bg=\"white\">A</ansi><ansi fg=\"red\" bg=\"cyan\">B</ansi><ansi fg=\"green\" bg=\"magenta\">C</ansi><ansi fg=\"yellow\" bg=\"blue\">D</ansi><ansi fg=\"blue\" bg=\"yellow\">E</ansi><ansi fg=\"magenta\" bg=\"green\">F</ansi><ansi fg=\"cyan\" bg=\"red\">G</ansi><ansi fg=\"white\" bg=\"black\">H</ansi> some more normal text... "
-		strlen := len(str)
+func printUsage() {
 
-		for {
-			for i := 0; i < strlen; i++ {
-				fmt.Print(string(str[i]))
-				time.Sleep(10 * time.Millisecond)
-			}
-		}
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	info, err := os.Stdin.Stat()
-	if err != nil {
-		panic(err)
+	fmt.Fprintf(os.Stderr, "\n%s %s <command> [--aliases file.yaml] ...\n\n",
+		ansitags.Parse("<ansi fg=red>Usage:</ansi>"), os.Args[0])
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-62s %s\n", name+" "+commands[name].args, commands[name].help)
 	}
+	fmt.Fprintf(os.Stderr, "\nCommands that write colors also take --color-profile 256|16|truecolor|mono.\n")
+	fmt.Fprintf(os.Stderr, "With no command, tagged text piped to stdin is rendered as ansi.\n\n")
+}
 
-	if info.Mode()&os.ModeCharDevice != 0 || info.Size() <= 0 {
+// runLegacy keeps the original flag based interface working:
+//
+// ./example/bin/ansitags -mode=generate | ./example/bin/ansitags
+func runLegacy(args []string) int {
 
-		fmt.Printf("\n%s %s\n\n",
-			ansitags.Parse("<ansi fg=red bold=true>Usage:</ansi>"),
-			"echo \"<ansi fg=red>Bingo</ansi>\" | "+os.Args[0])
+	flags := flag.NewFlagSet("ansitags", flag.ContinueOnError)
+	modePtr := flags.String("mode", "parse", "[parse|generate]")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if *modePtr == "generate" {
+		generate()
+	}
+
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
 
-		return
+	if info.Mode()&os.ModeCharDevice != 0 {
+		printUsage()
+		return exitOK
 	}
 
 	input := bufio.NewReader(os.Stdin)
@@ -54,4 +109,19 @@ func main() {
 		ansitags.ParseStreaming(input, output)
 	}
 
+	return exitOK
+}
+
+// generate writes sample tagged text forever, one byte at a time.
+func generate() {
+
+	str := "Some normal text <ansi fg=black bg=\"white\">A</ansi><ansi fg=\"red\" bg=\"cyan\">B</ansi><ansi fg=\"green\" bg=\"magenta\">C</ansi><ansi fg=\"yellow\" bg=\"blue\">D</ansi><ansi fg=\"blue\" bg=\"yellow\">E</ansi><ansi fg=\"magenta\" bg=\"green\">F</ansi><ansi fg=\"cyan\" bg=\"red\">G</ansi><ansi fg=\"white\" bg=\"black\">H</ansi> some more normal text... "
+	strlen := len(str)
+
+	for {
+		for i := 0; i < strlen; i++ {
+			fmt.Print(string(str[i]))
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
 }
@@ -0,0 +1,200 @@
+package ansitags
+
+import (
+	"strconv"
+	"strings"
+)
+
+const escByte byte = 0x1b
+
+// FromANSI converts text containing ANSI color escape codes back into tagged
+// text, the reverse of Parse. 16 color, 256 color and 24 bit color codes
+// (mapped to the nearest palette color) are supported. All other escape
+// sequences, such as cursor movement or text attributes, are dropped. Text
+// that looks like a tag is escaped, so it is printed as is.
+//
+// Usage:
+//
+// fmt.Println(ansitags.FromANSI("\033[31mred\033[0m text"))
+//
+// Output:
+// <ansi fg="1">red</ansi> text
+func FromANSI(str string) string {
+
+	var out strings.Builder
+	out.Grow(len(str))
+
+	fg, bg := defaultFg256, defaultBg256
+	openFg, openBg := defaultFg256, defaultBg256
+	tagOpened := false
+
+	// syncTag makes the currently open tag match fg and bg before text is written.
+	syncTag := func() {
+		if tagOpened && openFg == fg && openBg == bg {
+			return
+		}
+		if tagOpened {
+			out.WriteString("</ansi>")
+			tagOpened = false
+		}
+		if fg == defaultFg256 && bg == defaultBg256 {
+			return
+		}
+		out.WriteString("<ansi")
+		if fg != defaultFg256 {
+			out.WriteString(` fg="` + strconv.Itoa(fg) + `"`)
+		}
+		if bg != defaultBg256 {
+			out.WriteString(` bg="` + strconv.Itoa(bg) + `"`)
+		}
+		out.WriteString(">")
+		tagOpened = true
+		openFg, openBg = fg, bg
+	}
+
+	for i := 0; i < len(str); {
+
+		if str[i] != escByte {
+			end := strings.IndexByte(str[i:], escByte)
+			if end < 0 {
+				end = len(str)
+			} else {
+				end += i
+			}
+			syncTag()
+			out.WriteString(Escape(str[i:end]))
+			i = end
+			continue
+		}
+
+		seqLen, params, final := readEscape(str[i:])
+		if final == 'm' {
+			fg, bg = applySGR(params, fg, bg)
+		}
+		i += seqLen
+	}
+
+	if tagOpened {
+		out.WriteString("</ansi>")
+	}
+
+	return out.String()
+}
+
+// readEscape reads the escape sequence at the start of str, which must begin
+// with ESC. It returns the length of the sequence and, for CSI sequences, the
+// parameter bytes and final byte. An unterminated sequence runs to the end of
+// str.
+func readEscape(str string) (seqLen int, params string, final byte) {
+
+	if len(str) < 2 {
+		return len(str), "", 0
+	}
+
+	switch str[1] {
+	case '[': // CSI: parameters, intermediates, then a final byte in 0x40–0x7E
+		for i := 2; i < len(str); i++ {
+			if str[i] >= 0x40 && str[i] <= 0x7E {
+				return i + 1, str[2:i], str[i]
+			}
+		}
+		return len(str), "", 0
+	case ']', 'P', 'X', '^', '_': // OSC and other strings, terminated by BEL or ESC \
+		for i := 2; i < len(str); i++ {
+			if str[i] == 0x07 {
+				return i + 1, "", 0
+			}
+			if str[i] == escByte && i+1 < len(str) && str[i+1] == '\\' {
+				return i + 2, "", 0
+			}
+		}
+		return len(str), "", 0
+	}
+
+	return 2, "", 0
+}
+
+// applySGR applies the color changes of SGR parameters such as "38;5;196" to
+// fg and bg.
+func applySGR(params string, fg int, bg int) (int, int) {
+
+	codes := strings.Split(params, ";")
+
+	for i := 0; i < len(codes); i++ {
+
+		code, err := strconv.Atoi(codes[i])
+		if err != nil {
+			if codes[i] != "" {
+				continue
+			}
+			code = 0
+		}
+
+		switch {
+		case code == 0:
+			fg, bg = defaultFg256, defaultBg256
+		case code >= 30 && code <= 37:
+			fg = code - 30
+		case code >= 90 && code <= 97:
+			fg = code - 90 + 8
+		case code == 39:
+			fg = defaultFg256
+		case code >= 40 && code <= 47:
+			bg = code - 40
+		case code >= 100 && code <= 107:
+			bg = code - 100 + 8
+		case code == 49:
+			bg = defaultBg256
+		case code == 38 || code == 48:
+			colorCode, used := extendedColor(codes[i+1:])
+			i += used
+			if colorCode < 0 {
+				continue
+			}
+			if code == 38 {
+				fg = colorCode
+			} else {
+				bg = colorCode
+			}
+		}
+	}
+
+	return fg, bg
+}
+
+// extendedColor reads the "5;N" or "2;R;G;B" parameters that follow a 38 or 48
+// code. It returns the palette color (or -1 if invalid) and how many
+// parameters were consumed.
+func extendedColor(codes []string) (colorCode int, used int) {
+
+	if len(codes) == 0 {
+		return -1, 0
+	}
+
+	switch codes[0] {
+	case "5":
+		if len(codes) < 2 {
+			return -1, len(codes)
+		}
+		num, err := strconv.Atoi(codes[1])
+		if err != nil || num < 0 || num > 255 {
+			return -1, 2
+		}
+		return num, 2
+	case "2":
+		if len(codes) < 4 {
+			return -1, len(codes)
+		}
+		var rgbVals [3]uint8
+		for j := 0; j < 3; j++ {
+			num, err := strconv.Atoi(codes[j+1])
+			if err != nil || num < 0 || num > 255 {
+				return -1, 4
+			}
+			rgbVals[j] = uint8(num)
+		}
+		return nearestColor(rgbVals[0], rgbVals[1], rgbVals[2], 256), 4
+	}
+
+	return -1, 1
+}
@@ -0,0 +1,37 @@
+package ansitags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromANSI(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain", "no codes here", "no codes here"},
+		{"basic fg", "\033[31mred\033[0m text", `<ansi fg="1">red</ansi> text`},
+		{"bright fg and bg", "\033[91;104mx\033[m", `<ansi fg="9" bg="12">x</ansi>`},
+		{"256 colors", "\033[38;5;196m\033[48;5;17mx\033[0m", `<ansi fg="196" bg="17">x</ansi>`},
+		{"true color", "\033[38;2;255;0;255mx", `<ansi fg="13">x</ansi>`},
+		{"default fg keeps bg", "\033[31;44ma\033[39mb\033[49mc", `<ansi fg="1" bg="4">a</ansi><ansi bg="4">b</ansi>c`},
+		{"repeated codes", "\033[31ma\033[31mb", `<ansi fg="1">ab</ansi>`},
+		{"other sequences dropped", "\033[2J\033[1;1H\033]0;title\007\033[1mhi", "hi"},
+		{"unterminated", "hi\033[3", "hi"},
+		{"tag like text", "\033[31m<ansi fg=blue>\033[0m", `<ansi fg="1"><\ansi fg=blue></ansi>`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, FromANSI(tc.input))
+		})
+	}
+}
+
+func TestFromANSIRoundTrip(t *testing.T) {
+	input := `a <ansi fg="1" bg="4">b <ansi fg="200">c</ansi> d</ansi> e`
+	assert.Equal(t, Runs(input), Runs(FromANSI(Parse(input))))
+}
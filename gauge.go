@@ -0,0 +1,174 @@
+package ansitags
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+var (
+	// partialBlocks are the left aligned eighth blocks, from 1/8 to a full cell.
+	partialBlocks = []rune{'▏', '▎', '▍', '▌', '▋', '▊', '▉', '█'}
+
+	sparkLevels      = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+	sparkLevelsASCII = []rune{'_', '.', '-', '~', '=', '+', '*', '#'}
+)
+
+// ColorStop colors a gauge once it reaches a threshold.
+type ColorStop struct {
+	Threshold float64 // fraction from 0 to 1 at or above which Style is used
+	Style     string  // tag attributes or an alias, e.g. "green" or `fg="red" bg="black"`
+}
+
+// GaugeOptions controls how Bar and Sparkline draw.
+type GaugeOptions struct {
+	Fill       rune        // character for filled cells, default '█' or '#' in ASCII mode
+	Empty      rune        // character for empty cells, default '░' or '-' in ASCII mode
+	EmptyStyle string      // tag attributes or an alias for the empty cells
+	Stops      []ColorStop // colors by how full the gauge is, the highest stop reached is used
+	ASCII      bool        // only use ASCII characters
+}
+
+func (o GaugeOptions) withDefaults() GaugeOptions {
+	if o.Fill == 0 {
+		o.Fill = '█'
+		if o.ASCII {
+			o.Fill = '#'
+		}
+	}
+	if o.Empty == 0 {
+		o.Empty = '░'
+		if o.ASCII {
+			o.Empty = '-'
+		}
+	}
+	return o
+}
+
+// style returns the style of the highest stop at or below fraction.
+func (o GaugeOptions) style(fraction float64) string {
+
+	stops := append([]ColorStop(nil), o.Stops...)
+	sort.SliceStable(stops, func(i, j int) bool {
+		return stops[i].Threshold < stops[j].Threshold
+	})
+
+	style := ""
+	for _, stop := range stops {
+		if fraction >= stop.Threshold {
+			style = stop.Style
+		}
+	}
+	return style
+}
+
+// Bar returns a tagged progress bar width columns wide showing value out of
+// max. With the default fill the end of the bar is drawn to 1/8 of a column
+// using partial block characters, unless opts.ASCII is set. The filled part
+// is colored by opts.Stops.
+//
+// Usage:
+//
+//	hp := ansitags.Bar(37, 120, 20, ansitags.GaugeOptions{
+//		Stops: []ansitags.ColorStop{{0, "red"}, {0.3, "yellow"}, {0.6, "green"}},
+//	})
+//	fmt.Println(ansitags.Parse("HP " + hp))
+func Bar(value float64, max float64, width int, opts GaugeOptions) string {
+
+	if width <= 0 {
+		return ""
+	}
+
+	opts = opts.withDefaults()
+
+	fraction := 0.0
+	if max > 0 && !math.IsNaN(value/max) {
+		fraction = math.Min(math.Max(value/max, 0), 1)
+	}
+
+	cells := fraction * float64(width)
+	full := int(cells)
+
+	partial := ""
+	if opts.Fill == '█' && !opts.ASCII && full < width {
+		if eighths := int((cells - float64(full)) * 8); eighths > 0 {
+			partial = string(partialBlocks[eighths-1])
+		}
+	}
+
+	empty := width - full
+	if partial != "" {
+		empty--
+	}
+
+	// Escaped, so that a < fill can't start a tag with what follows it
+	return styled(opts.style(fraction), strings.Repeat(Escape(string(opts.Fill)), full)+partial) +
+		styled(opts.EmptyStyle, strings.Repeat(Escape(string(opts.Empty)), empty))
+}
+
+// Sparkline returns a tagged line of block characters, one per value, with
+// heights scaled between the smallest and largest value. Each character is
+// colored by the stops in opts, using where its value falls in that range.
+// NaN values are drawn as a space, and infinite values as the highest or
+// lowest level.
+//
+// Usage:
+//
+// fmt.Println(ansitags.Parse(ansitags.Sparkline(playersOnline)))
+func Sparkline(values []float64, opts ...GaugeOptions) string {
+
+	var o GaugeOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	levels := sparkLevels
+	if o.ASCII {
+		levels = sparkLevelsASCII
+	}
+
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			low = math.Min(low, v)
+			high = math.Max(high, v)
+		}
+	}
+
+	var out strings.Builder
+	var run strings.Builder
+	runStyle := ""
+
+	flush := func() {
+		out.WriteString(styled(runStyle, run.String()))
+		run.Reset()
+	}
+
+	for _, v := range values {
+		char := ' '
+		style := ""
+
+		if !math.IsNaN(v) {
+			fraction := 0.0
+			switch {
+			case math.IsInf(v, 1):
+				fraction = 1
+			case math.IsInf(v, -1):
+				fraction = 0
+			case high > low:
+				fraction = (v - low) / (high - low)
+			}
+			char = levels[int(math.Round(fraction*float64(len(levels)-1)))]
+			style = o.style(fraction)
+		}
+
+		if style != runStyle {
+			flush()
+			runStyle = style
+		}
+		run.WriteRune(char)
+	}
+	flush()
+
+	return out.String()
+}
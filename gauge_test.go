@@ -0,0 +1,76 @@
+package ansitags
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBar(t *testing.T) {
+	assert.Equal(t, "█████░░░░░", Bar(50, 100, 10, GaugeOptions{}))
+	assert.Equal(t, "██████████", Bar(150, 100, 10, GaugeOptions{}))
+	assert.Equal(t, "░░░░░░░░░░", Bar(-5, 100, 10, GaugeOptions{}))
+	assert.Equal(t, "░░░░░░░░░░", Bar(5, 0, 10, GaugeOptions{}))
+	assert.Equal(t, "", Bar(5, 10, 0, GaugeOptions{}))
+}
+
+func TestBarNotANumber(t *testing.T) {
+	assert.Equal(t, "░░░░░", Bar(math.NaN(), 10, 5, GaugeOptions{}))
+	assert.Equal(t, "░░░░░", Bar(math.Inf(1), math.Inf(1), 5, GaugeOptions{}))
+	assert.Equal(t, "█████", Bar(math.Inf(1), 10, 5, GaugeOptions{}))
+	assert.Equal(t, "░░░░░", Bar(5, math.Inf(1), 5, GaugeOptions{}))
+}
+
+func TestBarPartial(t *testing.T) {
+	// 4.5 cells is 4 full and half a block
+	assert.Equal(t, "████▌░░░░░", Bar(45, 100, 10, GaugeOptions{}))
+	assert.Equal(t, "█▎ ", Bar(9, 20, 3, GaugeOptions{Empty: ' '}))
+	assert.Equal(t, 10, VisibleWidth(Bar(33, 100, 10, GaugeOptions{})))
+}
+
+func TestBarASCII(t *testing.T) {
+	assert.Equal(t, "####------", Bar(45, 100, 10, GaugeOptions{ASCII: true}))
+	assert.Equal(t, "===.......", Bar(30, 100, 10, GaugeOptions{Fill: '=', Empty: '.'}))
+}
+
+func TestBarTagStartFill(t *testing.T) {
+	result := Bar(40, 100, 5, GaugeOptions{Fill: '<', Empty: '<', EmptyStyle: "blue"})
+	assert.Equal(t, `<\<\<ansi fg="blue"><\<\<\</ansi>`, result)
+	assert.Equal(t, "<<\x1b[38;5;4m\x1b[49m<<<\x1b[0m", Parse(result))
+	assert.Equal(t, 5, VisibleWidth(result))
+}
+
+func TestBarStops(t *testing.T) {
+	opts := GaugeOptions{
+		Stops:      []ColorStop{{0.6, "green"}, {0, "red"}, {0.3, "yellow"}},
+		EmptyStyle: `fg="black"`,
+		ASCII:      true,
+	}
+
+	assert.Equal(t, `<ansi fg="green">#######</ansi><ansi fg="black">---</ansi>`, Bar(70, 100, 10, opts))
+	assert.Equal(t, `<ansi fg="yellow">###</ansi><ansi fg="black">-------</ansi>`, Bar(30, 100, 10, opts))
+	assert.Equal(t, `<ansi fg="red">#</ansi><ansi fg="black">---------</ansi>`, Bar(10, 100, 10, opts))
+	assert.Equal(t, `<ansi fg="black">----------</ansi>`, Bar(0, 100, 10, opts))
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁▂▃▄▅▆▇█", Sparkline([]float64{0, 1, 2, 3, 4, 5, 6, 7}))
+	assert.Equal(t, "▁█▁", Sparkline([]float64{10, 20, 10}))
+	assert.Equal(t, "▁▁▁", Sparkline([]float64{5, 5, 5}))
+	assert.Equal(t, "▁ █", Sparkline([]float64{1, math.NaN(), 2}))
+	assert.Equal(t, "", Sparkline(nil))
+	assert.Equal(t, "_~#", Sparkline([]float64{0, 0.4, 1}, GaugeOptions{ASCII: true}))
+}
+
+func TestSparklineInfinite(t *testing.T) {
+	assert.Equal(t, "▁█", Sparkline([]float64{1, math.Inf(1)}))
+	assert.Equal(t, "▁▁", Sparkline([]float64{math.Inf(-1), 1}))
+	assert.Equal(t, "▁▁█ ", Sparkline([]float64{math.Inf(-1), 1, 2, math.NaN()}))
+	assert.Equal(t, "█▁", Sparkline([]float64{math.Inf(1), math.Inf(-1)}))
+}
+
+func TestSparklineStops(t *testing.T) {
+	opts := GaugeOptions{Stops: []ColorStop{{0, "blue"}, {0.5, "red"}}}
+	assert.Equal(t, `<ansi fg="blue">▁▃</ansi><ansi fg="red">▆█</ansi>`, Sparkline([]float64{0, 1, 2, 3}, opts))
+}
@@ -0,0 +1,177 @@
+package ansitags
+
+import (
+	"sort"
+	"strings"
+)
+
+// Matcher finds matches in the visible text of a tagged string.
+// *regexp.Regexp satisfies it, and Literal returns one for plain text.
+type Matcher interface {
+	// FindAllStringIndex returns the [start, end) byte offsets of up to n
+	// successive matches in s, or all matches if n is negative.
+	FindAllStringIndex(s string, n int) [][]int
+}
+
+type literalMatcher string
+
+// Literal returns a Matcher for exact, case sensitive occurrences of s.
+func Literal(s string) Matcher {
+	return literalMatcher(s)
+}
+
+func (l literalMatcher) FindAllStringIndex(s string, n int) [][]int {
+
+	if len(l) == 0 {
+		return nil
+	}
+
+	var matches [][]int
+	for offset := 0; n < 0 || len(matches) < n; {
+		i := strings.Index(s[offset:], string(l))
+		if i < 0 {
+			break
+		}
+		matches = append(matches, []int{offset + i, offset + i + len(l)})
+		offset += i + len(l)
+	}
+
+	return matches
+}
+
+// Highlight wraps every match of pattern in a tag built from style, which
+// is either tag attributes such as `bg="yellow" fg="black"` or a bare alias
+// for the foreground. Matching is done against the visible text only, so
+// tag names and attribute values never match, and a match may span tags.
+// Where a match crosses one of the string's own tags, the highlight is
+// closed and reopened around it so the tags stay balanced and the original
+// colors come back after the match. A < right before an added tag is
+// escaped, since Parse wouldn't read the tag otherwise.
+//
+// Usage:
+//
+// ansitags.Highlight(helpText, regexp.MustCompile(`(?i)fireball`), `bg="yellow" fg="black"`)
+// ansitags.Highlight(logLine, ansitags.Literal("Bob"), "red")
+func Highlight(str string, pattern Matcher, style string) string {
+
+	open := styleTag(style)
+	if open == "" {
+		return str
+	}
+
+	tokens := tokenize(str)
+	spans := visibleMatches(tokens, pattern)
+	if len(spans) == 0 {
+		return str
+	}
+
+	var out strings.Builder
+	out.Grow(len(str) + len(spans)*(len(open)+len("</ansi>")))
+
+	inMatch := false
+	offset := 0 // offset into the visible text
+	next := 0   // index of the next span to start or end
+
+	for _, tok := range tokens {
+		if tok.kind != tokenText {
+			if inMatch {
+				escapeTagStartBefore(&out)
+				out.WriteString("</ansi>" + tok.raw + open)
+			} else {
+				out.WriteString(tok.raw)
+			}
+			continue
+		}
+
+		text := tok.raw
+		for len(text) > 0 {
+			// Offset of the next span boundary, or past this token
+			boundary := offset + len(text)
+			if next < len(spans) {
+				if inMatch && spans[next][1] < boundary {
+					boundary = spans[next][1]
+				} else if !inMatch && spans[next][0] < boundary {
+					boundary = spans[next][0]
+				}
+			}
+
+			out.WriteString(text[:boundary-offset])
+			text = text[boundary-offset:]
+			offset = boundary
+
+			if next < len(spans) {
+				if !inMatch && offset == spans[next][0] && len(text) > 0 {
+					escapeTagStartBefore(&out)
+					out.WriteString(open)
+					inMatch = true
+				}
+				if inMatch && offset == spans[next][1] {
+					escapeTagStartBefore(&out)
+					out.WriteString("</ansi>")
+					inMatch = false
+					next++
+				}
+			}
+		}
+	}
+
+	if inMatch {
+		escapeTagStartBefore(&out)
+		out.WriteString("</ansi>")
+	}
+
+	return out.String()
+}
+
+// visibleMatches runs pattern over the visible text of tokens and returns
+// the matches as offsets into that text, see matchSpans.
+func visibleMatches(tokens []token, pattern Matcher) [][2]int {
+
+	var plain strings.Builder
+	for _, tok := range tokens {
+		if tok.kind == tokenText {
+			plain.WriteString(tok.raw)
+		}
+	}
+
+	return matchSpans(plain.String(), pattern)
+}
+
+// matchSpans runs pattern over text as it is printed, with escapes undone,
+// and returns the non-empty matches as offsets into text, widened to whole
+// grapheme clusters and merged where they overlap.
+func matchSpans(text string, pattern Matcher) [][2]int {
+
+	plain, offsets := unescapeOffsets(text)
+
+	var boundaries []int
+	for i := 0; i < len(text); {
+		boundaries = append(boundaries, i)
+		i += clusterSize(text[i:])
+	}
+	boundaries = append(boundaries, len(text))
+
+	var spans [][2]int
+	for _, m := range pattern.FindAllStringIndex(plain, -1) {
+		if m[1] <= m[0] {
+			continue
+		}
+		if offsets != nil {
+			m = []int{offsets[m[0]], offsets[m[1]]}
+		}
+
+		// Snap the start down and the end up to cluster boundaries
+		start := boundaries[sort.SearchInts(boundaries, m[0]+1)-1]
+		end := boundaries[sort.SearchInts(boundaries, m[1])]
+
+		if n := len(spans); n > 0 && start <= spans[n-1][1] {
+			if end > spans[n-1][1] {
+				spans[n-1][1] = end
+			}
+			continue
+		}
+		spans = append(spans, [2]int{start, end})
+	}
+
+	return spans
+}
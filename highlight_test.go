@@ -0,0 +1,77 @@
+package ansitags
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlightLiteral(t *testing.T) {
+	assert.Equal(t, `the <ansi fg="red">cat</ansi> and the <ansi fg="red">cat</ansi>`, Highlight("the cat and the cat", Literal("cat"), "red"))
+	assert.Equal(t, "no match", Highlight("no match", Literal("cat"), "red"))
+	assert.Equal(t, "empty", Highlight("empty", Literal(""), "red"))
+	assert.Equal(t, "no style", Highlight("no style", Literal("style"), ""))
+}
+
+func TestHighlightRegexp(t *testing.T) {
+	re := regexp.MustCompile(`(?i)fire\w*`)
+	result := Highlight("Fireball and firebolt", re, `bg="yellow" fg="black"`)
+	assert.Equal(t, `<ansi bg="yellow" fg="black">Fireball</ansi> and <ansi bg="yellow" fg="black">firebolt</ansi>`, result)
+}
+
+func TestHighlightIgnoresTags(t *testing.T) {
+	// Attribute values and tag names are never matched
+	input := `<ansi fg="red">red</ansi> ansi`
+	assert.Equal(t, `<ansi fg="red"><ansi fg="blue">red</ansi></ansi> <ansi fg="blue">ansi</ansi>`, Highlight(input, regexp.MustCompile(`red|ansi`), "blue"))
+}
+
+func TestHighlightAcrossTags(t *testing.T) {
+	input := `The <ansi fg="red">Grand</ansi> Hall`
+
+	assert.Equal(t, `The <ansi fg="red">Gr<ansi bg="blue">and</ansi></ansi><ansi bg="blue"> Ha</ansi>ll`,
+		Highlight(input, Literal("and Ha"), `bg="blue"`))
+
+	assert.Equal(t, `Th<ansi bg="blue">e </ansi><ansi fg="red"><ansi bg="blue">Gr</ansi>and</ansi> Hall`,
+		Highlight(input, Literal("e Gr"), `bg="blue"`))
+}
+
+func TestHighlightRestoresColors(t *testing.T) {
+	input := `<ansi fg="green">You see a sword here.</ansi>`
+	result := Highlight(input, Literal("sword"), "yellow")
+
+	assert.Equal(t, `<ansi fg="green">You see a <ansi fg="yellow">sword</ansi> here.</ansi>`, result)
+	assert.Equal(t, Parse(input, StripTags), Parse(result, StripTags))
+}
+
+func TestHighlightGraphemes(t *testing.T) {
+	// A match in the middle of a cluster takes the whole cluster
+	input := "cafés"
+	assert.Equal(t, "caf<ansi fg=\"red\">é</ansi>s", Highlight(input, Literal("e"), "red"))
+}
+
+func TestHighlightEscaped(t *testing.T) {
+	// Matching sees the text as printed, and never splits an escape
+	input := `if a <\ b`
+	assert.Equal(t, `if a <ansi fg="red"><\ b</ansi>`, Highlight(input, Literal("< b"), "red"))
+	assert.Equal(t, `if a <ansi fg="red"><\</ansi> b`, Highlight(input, regexp.MustCompile(`<`), "red"))
+	assert.Equal(t, input, Highlight(input, Literal(`\`), "red"))
+}
+
+func TestHighlightAfterTagStart(t *testing.T) {
+	// Parse doesn't start a tag right after a <, so that < is escaped
+	result := Highlight("say <Bob>", Literal("Bob"), "red")
+	assert.Equal(t, `say <\<ansi fg="red">Bob</ansi>>`, result)
+	assert.Equal(t, "say <\x1b[38;5;1m\x1b[49mBob\x1b[0m>", Parse(result))
+
+	// Or one at the end of the match
+	result = Highlight("a<b", Literal("a<"), "red")
+	assert.Equal(t, `<ansi fg="red">a<\</ansi>b`, result)
+	assert.Equal(t, "a<b", Parse(result, StripTags))
+}
+
+func TestLiteralMatcher(t *testing.T) {
+	assert.Equal(t, [][]int{{0, 2}, {2, 4}}, Literal("aa").FindAllStringIndex("aaaaa", -1))
+	assert.Equal(t, [][]int{{0, 2}}, Literal("aa").FindAllStringIndex("aaaaa", 1))
+	assert.Nil(t, Literal("b").FindAllStringIndex("aaaaa", -1))
+}
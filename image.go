@@ -0,0 +1,118 @@
+package ansitags
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"unicode/utf8"
+)
+
+// ImageOptions controls how RenderImage draws text. Zero values fall back to
+// the defaults noted on each field.
+type ImageOptions struct {
+	Scale      int         // pixel size of one font dot, default 2 (16x16 pixel cells)
+	Padding    int         // empty border around the text in pixels, default 0
+	Palette    *Palette    // default DefaultPalette()
+	Foreground color.Color // color of untagged text, default palette color 7
+	Background color.Color // image background, default palette color 0
+}
+
+func (o ImageOptions) withDefaults() ImageOptions {
+	if o.Scale <= 0 {
+		o.Scale = 2
+	}
+	if o.Padding < 0 {
+		o.Padding = 0
+	}
+	if o.Palette == nil {
+		o.Palette = DefaultPalette()
+	}
+	if o.Foreground == nil {
+		o.Foreground = o.Palette[7]
+	}
+	if o.Background == nil {
+		o.Background = o.Palette[0]
+	}
+	return o
+}
+
+// RenderImage draws a tagged string onto an RGBA image using the built-in 8x8
+// bitmap font, so no external font files are needed. Characters the font does
+// not know are drawn as '?'. The result can be written out with image/png.
+//
+// Usage:
+//
+// img := ansitags.RenderImage(`<ansi fg="red">Hello</ansi> world`, ansitags.ImageOptions{})
+// f, _ := os.Create("hello.png")
+// png.Encode(f, img)
+func RenderImage(str string, opts ImageOptions) image.Image {
+
+	opts = opts.withDefaults()
+
+	lines := runLines(Runs(str))
+	cell := fontSize * opts.Scale
+
+	columns := 0
+	for _, line := range lines {
+		lineLen := 0
+		for _, run := range line {
+			lineLen += plainWidth(run.Text, lineLen)
+		}
+		if lineLen > columns {
+			columns = lineLen
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, columns*cell+opts.Padding*2, len(lines)*cell+opts.Padding*2))
+	draw.Draw(img, img.Bounds(), image.NewUniform(opts.Background), image.Point{}, draw.Src)
+
+	for row, line := range lines {
+		y := opts.Padding + row*cell
+		col := 0
+		for _, run := range line {
+
+			var fg color.Color = opts.Foreground
+			if run.Fg != nil {
+				fg = opts.Palette[run.Fg.Index]
+			}
+
+			for i := 0; i < len(run.Text); {
+				size := graphemeSize(run.Text[i:])
+				cluster := run.Text[i : i+size]
+				i += size
+
+				width := clusterWidth(cluster, col)
+				if width == 0 {
+					continue
+				}
+
+				x := opts.Padding + col*cell
+				if run.Bg != nil {
+					draw.Draw(img, image.Rect(x, y, x+width*cell, y+cell), image.NewUniform(opts.Palette[run.Bg.Index]), image.Point{}, draw.Src)
+				}
+				if cluster[0] != '\t' {
+					r, _ := utf8.DecodeRuneInString(cluster)
+					drawGlyph(img, x, y, opts.Scale, glyph(r), fg)
+				}
+				col += width
+			}
+		}
+	}
+
+	return img
+}
+
+// drawGlyph paints the set pixels of g with its top left corner at x, y.
+func drawGlyph(img *image.RGBA, x int, y int, scale int, g [fontSize]byte, c color.Color) {
+	src := image.NewUniform(c)
+	for gy, bits := range g {
+		for gx := 0; gx < fontSize; gx++ {
+			if bits&(1<<gx) == 0 {
+				continue
+			}
+			px := x + gx*scale
+			py := y + gy*scale
+			draw.Draw(img, image.Rect(px, py, px+scale, py+scale), src, image.Point{}, draw.Src)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package ansitags
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderImageBounds(t *testing.T) {
+	img := RenderImage("abc\nde", ImageOptions{Scale: 1, Padding: 2})
+
+	assert.Equal(t, 3*8+4, img.Bounds().Dx())
+	assert.Equal(t, 2*8+4, img.Bounds().Dy())
+}
+
+func TestRenderImageColors(t *testing.T) {
+	img := RenderImage(`<ansi fg="red" bg="blue">█</ansi> `, ImageOptions{Scale: 1})
+
+	assert.Equal(t, color.RGBAModel.Convert(DefaultPalette()[1]), img.At(3, 3))
+	assert.Equal(t, color.RGBAModel.Convert(DefaultPalette()[0]), img.At(8+3, 3))
+
+	img = RenderImage(`<ansi bg="blue"> </ansi>`, ImageOptions{Scale: 1})
+	assert.Equal(t, color.RGBAModel.Convert(DefaultPalette()[4]), img.At(3, 3))
+}
+
+func TestRenderImageCustomColors(t *testing.T) {
+	img := RenderImage("_", ImageOptions{Scale: 2, Foreground: color.White, Background: color.Black})
+
+	assert.Equal(t, color.RGBAModel.Convert(color.White), img.At(0, 15))
+	assert.Equal(t, color.RGBAModel.Convert(color.Black), img.At(0, 0))
+}
+
+func TestGlyphFallback(t *testing.T) {
+	assert.Equal(t, glyph('?'), glyph('☃'))
+	assert.Equal(t, [8]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, glyph('█'))
+	assert.Equal(t, [8]byte{0, 0, 0, 0, 0, 0, 0, 0xFF}, glyph('▁'))
+}
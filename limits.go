@@ -0,0 +1,124 @@
+package ansitags
+
+import (
+	"bytes"
+	"errors"
+)
+
+// LimitAction selects what the parser does once input goes over a limit.
+type LimitAction uint8
+
+const (
+	LimitFlatten  LimitAction = iota // keep writing the text, but ignore the tags that go over the limit
+	LimitTruncate                    // stop at the tag that goes over the limit, closing the open tags
+	LimitError                       // as LimitTruncate, and ParseLimited returns ErrLimitExceeded
+)
+
+// expansionAllowance is how many bytes of output every input may produce on
+// top of Limits.MaxExpansion, so that short strings aren't caught by it.
+const expansionAllowance = 256
+
+// ErrLimitExceeded is returned by ParseLimited when the input goes over one
+// of the limits and the action is LimitError.
+var ErrLimitExceeded = errors.New("ansitags: limit exceeded")
+
+// Limits caps the work hostile input can make the parser do. A zero field
+// means no limit.
+type Limits struct {
+	MaxDepth     int         // deepest tags may be nested
+	MaxTags      int         // most open and close tags in one input
+	MaxExpansion float64     // most output bytes per byte of input, e.g. 4
+	Action       LimitAction // what to do once a limit is hit
+}
+
+// limits is used by Parse and ParseStreaming. Guarded by rwLock.
+var limits Limits
+
+// SetLimits sets the limits used by Parse and ParseStreaming. By default
+// there are none. Since Parse and ParseStreaming can't return an error,
+// LimitError acts as LimitTruncate for them, and ParseStreaming leaves the
+// rest of its input unread.
+//
+// Usage:
+//
+// ansitags.SetLimits(ansitags.Limits{MaxDepth: 16, MaxTags: 500, MaxExpansion: 4, Action: ansitags.LimitFlatten})
+func SetLimits(l Limits) {
+	rwLock.Lock()
+	defer rwLock.Unlock()
+	limits = l
+}
+
+// ParseLimited parses str like Parse, but with its own limits rather than
+// those set with SetLimits. With LimitError, the output up to the tag that
+// went over the limit is returned along with ErrLimitExceeded.
+//
+// Usage:
+//
+// out, err := ansitags.ParseLimited(description, ansitags.Limits{MaxDepth: 8, Action: ansitags.LimitError})
+func ParseLimited(str string, l Limits, behaviors ...ParseBehavior) (string, error) {
+
+	var outputBuffer bytes.Buffer
+	outputBuffer.Grow(len(str))
+	err := parseString(str, &outputBuffer, &l, behaviors...)
+	return outputBuffer.String(), err
+}
+
+type limitResult uint8
+
+const (
+	limitApply limitResult = iota // handle the tag as usual
+	limitSkip                     // ignore the tag
+	limitStop                     // stop parsing
+)
+
+// limiter counts a single parse against its Limits.
+type limiter struct {
+	Limits
+	read      int  // input bytes read so far
+	written   int  // escape code bytes written so far
+	tags      int  // tags seen so far
+	flattened int  // open tags ignored for being too deep, whose close tags are ignored too
+	off       bool // a limit was hit with LimitFlatten, so tags are all ignored from here on
+	exceeded  bool
+}
+
+// tag decides what happens to the next open (or close) tag, which is seen
+// with stackLen tags open and would write codeLen bytes of escape codes.
+func (l *limiter) tag(open bool, stackLen int, codeLen int) limitResult {
+
+	l.tags++
+
+	switch {
+	case l.off:
+		return limitSkip
+	case l.MaxTags > 0 && l.tags > l.MaxTags,
+		l.MaxExpansion > 0 && float64(l.read+l.written+codeLen) > l.MaxExpansion*float64(l.read)+expansionAllowance:
+		l.exceeded = true
+		if l.Action == LimitFlatten {
+			l.off = true
+			return limitSkip
+		}
+		return limitStop
+	case !open && l.flattened > 0:
+		l.flattened--
+		return limitSkip
+	case open && l.MaxDepth > 0 && stackLen >= l.MaxDepth:
+		l.exceeded = true
+		if l.Action == LimitFlatten {
+			l.flattened++
+			return limitSkip
+		}
+		return limitStop
+	}
+
+	l.written += codeLen
+	return limitApply
+}
+
+// err returns ErrLimitExceeded if a limit was hit with LimitError.
+func (l *limiter) err() error {
+	if l.exceeded && l.Action == LimitError {
+		return ErrLimitExceeded
+	}
+	return nil
+}
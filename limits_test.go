@@ -0,0 +1,97 @@
+package ansitags
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimitedDepth(t *testing.T) {
+	input := `<ansi fg=1>a<ansi fg=2>b<ansi fg=3>c</ansi>d</ansi>e</ansi>f`
+
+	flat, err := ParseLimited(input, Limits{MaxDepth: 2, Action: LimitFlatten}, StripTags)
+	assert.NoError(t, err)
+	assert.Equal(t, "abcdef", flat)
+
+	// The third tag and its close tag are ignored, so c and d stay color 2
+	flat, _ = ParseLimited(input, Limits{MaxDepth: 2, Action: LimitFlatten})
+	expected, _ := ParseLimited(`<ansi fg=1>a<ansi fg=2>bcd</ansi>e</ansi>f`, Limits{})
+	assert.Equal(t, expected, flat)
+
+	truncated, err := ParseLimited(input, Limits{MaxDepth: 2, Action: LimitTruncate})
+	assert.NoError(t, err)
+	assert.Equal(t, Parse(`<ansi fg=1>a<ansi fg=2>b`), truncated)
+
+	truncated, err = ParseLimited(input, Limits{MaxDepth: 2, Action: LimitError})
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	assert.Equal(t, Parse(`<ansi fg=1>a<ansi fg=2>b`), truncated)
+}
+
+func TestParseLimitedTags(t *testing.T) {
+	input := `<ansi fg=1>a</ansi><ansi fg=2>b</ansi><ansi fg=3>c</ansi>`
+
+	result, err := ParseLimited(input, Limits{MaxTags: 4, Action: LimitFlatten})
+	assert.NoError(t, err)
+	assert.Equal(t, Parse(`<ansi fg=1>a</ansi><ansi fg=2>b</ansi>c`), result)
+
+	result, err = ParseLimited(input, Limits{MaxTags: 3, Action: LimitError})
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	assert.Equal(t, Parse(`<ansi fg=1>a</ansi><ansi fg=2>b`), result)
+
+	result, err = ParseLimited(input, Limits{MaxTags: 6, Action: LimitError})
+	assert.NoError(t, err)
+	assert.Equal(t, Parse(input), result)
+}
+
+func TestParseLimitedExpansion(t *testing.T) {
+	// Every close tag writes the parent's codes again
+	input := strings.Repeat(`<ansi fg=1 bg=2>`, 8) + strings.Repeat(`<ansi></ansi>`, 200)
+	unlimited := Parse(input)
+
+	result, err := ParseLimited(input, Limits{MaxExpansion: 2, Action: LimitError})
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	assert.LessOrEqual(t, len(result), 2*len(input)+expansionAllowance)
+	assert.Less(t, len(result), len(unlimited))
+
+	result, err = ParseLimited(input, Limits{MaxExpansion: 2, Action: LimitFlatten})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, len(result), 2*len(input)+expansionAllowance)
+	assert.True(t, strings.HasSuffix(result, ansiResetAll))
+
+	// Short strings get the allowance
+	result, err = ParseLimited(`<ansi fg=1 bg=2>x</ansi>`, Limits{MaxExpansion: 1, Action: LimitError})
+	assert.NoError(t, err)
+	assert.Equal(t, Parse(`<ansi fg=1 bg=2>x</ansi>`), result)
+}
+
+func TestParseLimitedNoLimits(t *testing.T) {
+	input := strings.Repeat(`<ansi fg=1>`, 100) + "x" + strings.Repeat(`</ansi>`, 100)
+	result, err := ParseLimited(input, Limits{Action: LimitError})
+	assert.NoError(t, err)
+	assert.Equal(t, Parse(input), result)
+}
+
+func TestSetLimits(t *testing.T) {
+	input := `<ansi fg=1>a<ansi fg=2>b<ansi fg=3>c</ansi></ansi></ansi>`
+	expected, _ := ParseLimited(input, Limits{MaxDepth: 1, Action: LimitTruncate})
+	unlimited := Parse(input)
+
+	SetLimits(Limits{MaxDepth: 1, Action: LimitError})
+	defer SetLimits(Limits{})
+
+	assert.Equal(t, expected, Parse(input))
+
+	var out strings.Builder
+	writer := bufio.NewWriter(&out)
+	ParseStreaming(bufio.NewReader(strings.NewReader(input)), writer)
+	writer.Flush()
+	assert.Equal(t, expected, out.String())
+
+	// ParseLimited ignores the package limits
+	result, err := ParseLimited(input, Limits{})
+	assert.NoError(t, err)
+	assert.Equal(t, unlimited, result)
+	assert.NotEqual(t, expected, result)
+}
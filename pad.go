@@ -0,0 +1,109 @@
+package ansitags
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// PadRight pads str on the right with fill until it is width visible columns
+// wide. The padding goes after any closing tags at the end of str, unless
+// inside is set, in which case it goes before them so that a background
+// color carries on through the padding. Strings that are already wide enough
+// are returned unchanged.
+//
+// Usage:
+//
+// ansitags.PadRight(`<ansi fg="red">Bob</ansi>`, 10, ' ')
+// ansitags.PadRight(`<ansi bg="blue">Bob</ansi>`, 10, ' ', true)
+func PadRight(str string, width int, fill rune, inside ...bool) string {
+
+	extra := width - VisibleWidth(str)
+	if extra <= 0 {
+		return str
+	}
+
+	if len(inside) > 0 && inside[0] {
+		head, body, tail := splitOuterTags(str)
+		return head + body + padding(extra, fill) + tail
+	}
+
+	return str + padding(extra, fill)
+}
+
+// PadLeft pads str on the left with fill until it is width visible columns
+// wide. The padding goes before any opening tags at the start of str, unless
+// inside is set, in which case it goes after them.
+func PadLeft(str string, width int, fill rune, inside ...bool) string {
+
+	extra := width - VisibleWidth(str)
+	if extra <= 0 {
+		return str
+	}
+
+	if len(inside) > 0 && inside[0] {
+		head, body, tail := splitOuterTags(str)
+		return head + padding(extra, fill) + body + tail
+	}
+
+	return padding(extra, fill) + str
+}
+
+// Center pads str on both sides with fill until it is width visible columns
+// wide. If the padding can't be split evenly the extra column goes on the
+// right. As with PadRight, inside puts the padding within the outer tags.
+func Center(str string, width int, fill rune, inside ...bool) string {
+
+	extra := width - VisibleWidth(str)
+	if extra <= 0 {
+		return str
+	}
+
+	left := padding(extra/2, fill)
+	right := padding(extra-extra/2, fill)
+
+	if len(inside) > 0 && inside[0] {
+		head, body, tail := splitOuterTags(str)
+		return head + left + body + right + tail
+	}
+
+	return left + str + right
+}
+
+// padding returns n columns of fill. If fill is a wide character and n is
+// odd, the last column is a space. A < fill is escaped so that it can't
+// start a tag with the text next to it.
+func padding(n int, fill rune) string {
+
+	fillWidth := runeWidth(fill)
+	if fillWidth == 0 || fill == utf8.RuneError {
+		fill = ' '
+		fillWidth = 1
+	}
+
+	pad := strings.Repeat(Escape(string(fill)), n/fillWidth)
+	if n%fillWidth > 0 {
+		pad += strings.Repeat(" ", n%fillWidth)
+	}
+
+	return pad
+}
+
+// splitOuterTags splits str into the opening tags it starts with, the body,
+// and the closing tags it ends with.
+func splitOuterTags(str string) (head string, body string, tail string) {
+
+	tokens := tokenize(str)
+
+	start := 0
+	i := 0
+	for ; i < len(tokens) && tokens[i].kind == tokenOpen; i++ {
+		start += len(tokens[i].raw)
+	}
+
+	end := len(str)
+	for j := len(tokens) - 1; j >= i && tokens[j].kind == tokenClose; j-- {
+		end -= len(tokens[j].raw)
+	}
+
+	return str[:start], str[start:end], str[end:]
+}
@@ -0,0 +1,51 @@
+package ansitags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPadRight(t *testing.T) {
+	assert.Equal(t, "Bob       ", PadRight("Bob", 10, ' '))
+	assert.Equal(t, `<ansi fg="red">Bob</ansi>.......`, PadRight(`<ansi fg="red">Bob</ansi>`, 10, '.'))
+	assert.Equal(t, `<ansi bg="blue"><ansi fg="red">Bob       </ansi></ansi>`, PadRight(`<ansi bg="blue"><ansi fg="red">Bob</ansi></ansi>`, 10, ' ', true))
+	assert.Equal(t, "Too long", PadRight("Too long", 4, ' '))
+}
+
+func TestPadLeft(t *testing.T) {
+	assert.Equal(t, "       Bob", PadLeft("Bob", 10, ' '))
+	assert.Equal(t, `-------<ansi fg="red">Bob</ansi>`, PadLeft(`<ansi fg="red">Bob</ansi>`, 10, '-'))
+	assert.Equal(t, `<ansi bg="blue">       Bob</ansi>`, PadLeft(`<ansi bg="blue">Bob</ansi>`, 10, ' ', true))
+}
+
+func TestCenter(t *testing.T) {
+	assert.Equal(t, "   Bob    ", Center("Bob", 10, ' '))
+	assert.Equal(t, `**<ansi fg="red">Bob</ansi>**`, Center(`<ansi fg="red">Bob</ansi>`, 7, '*'))
+	assert.Equal(t, `<ansi bg="blue">  Bob  </ansi>`, Center(`<ansi bg="blue">Bob</ansi>`, 7, ' ', true))
+}
+
+func TestPadWide(t *testing.T) {
+	// Wide text is measured by its columns
+	assert.Equal(t, "日本  ", PadRight("日本", 6, ' '))
+	// A wide fill that can't fill the last column is finished with a space
+	assert.Equal(t, "ab＝＝ ", PadRight("ab", 7, '＝'))
+	assert.Equal(t, 7, VisibleWidth(PadRight("ab", 7, '＝')))
+}
+
+func TestPadTagStartFill(t *testing.T) {
+	// The fill can't join up with the text to make a tag
+	result := PadLeft("ansi>x", 8, '<')
+	assert.Equal(t, `<\<\ansi>x`, result)
+	assert.Equal(t, "<<ansi>x", Parse(result))
+	assert.Equal(t, 8, VisibleWidth(result))
+
+	assert.Equal(t, "x<<", Parse(PadRight("x", 3, '<')))
+}
+
+func TestPadPartialTags(t *testing.T) {
+	// Padding inside only skips tags at the very start and end
+	input := `<ansi fg="red">A</ansi> and <ansi fg="blue">B</ansi>`
+	assert.Equal(t, `<ansi fg="red">A</ansi> and <ansi fg="blue">B  </ansi>`, PadRight(input, 9, ' ', true))
+	assert.Equal(t, `<ansi fg="red">  A</ansi> and <ansi fg="blue">B</ansi>`, PadLeft(input, 9, ' ', true))
+}
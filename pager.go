@@ -0,0 +1,138 @@
+package ansitags
+
+// Pager splits tagged text into screens of wrapped lines, for --More--
+// style prompts. Every line closes the tags open at its end and reopens them
+// at the start of the next, so a page always starts in the right colors no
+// matter where it begins.
+//
+// Usage:
+//
+//	p := ansitags.NewPager(helpText, 80, 24)
+//	for {
+//		for _, line := range p.Current() {
+//			fmt.Println(ansitags.Parse(line))
+//		}
+//		if !p.More() {
+//			break
+//		}
+//		waitForKey("--More--")
+//		p.Next()
+//	}
+type Pager struct {
+	lines  []string
+	height int
+	top    int // first line shown
+}
+
+// NewPager wraps text to width columns and splits it into pages of height
+// lines. Wrapping can be adjusted with opts, as with Wrap.
+func NewPager(text string, width int, height int, opts ...WrapOptions) *Pager {
+
+	var o WrapOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	if height < 1 {
+		height = 1
+	}
+
+	return &Pager{
+		lines:  Wrap(text, width, o),
+		height: height,
+	}
+}
+
+// Lines returns the total number of wrapped lines.
+func (p *Pager) Lines() int {
+	return len(p.lines)
+}
+
+// Pages returns the number of pages.
+func (p *Pager) Pages() int {
+	return (len(p.lines) + p.height - 1) / p.height
+}
+
+// Page returns the lines of page n, counting from 0, or nil if there is no
+// such page. It does not move the pager.
+func (p *Pager) Page(n int) []string {
+	if n < 0 || n >= p.Pages() {
+		return nil
+	}
+	return p.window(n * p.height)
+}
+
+// Current returns the lines on screen, starting at the current line.
+func (p *Pager) Current() []string {
+	return p.window(p.top)
+}
+
+// Line returns the index of the first line on screen.
+func (p *Pager) Line() int {
+	return p.top
+}
+
+// PageNumber returns the page the first line on screen is on, counting from 0.
+func (p *Pager) PageNumber() int {
+	return p.top / p.height
+}
+
+// SeekPage moves to the start of page n, counting from 0. Out of range pages
+// are clamped to the first or last page.
+func (p *Pager) SeekPage(n int) {
+	if last := p.Pages() - 1; n > last {
+		n = last
+	}
+	if n < 0 {
+		n = 0
+	}
+	p.top = n * p.height
+}
+
+// SeekLine moves so that line n, counting from 0, is the first on screen.
+// Out of range lines are clamped to the first or last line.
+func (p *Pager) SeekLine(n int) {
+	if n >= len(p.lines) {
+		n = len(p.lines) - 1
+	}
+	if n < 0 {
+		n = 0
+	}
+	p.top = n
+}
+
+// More reports whether there are lines after the current screen.
+func (p *Pager) More() bool {
+	return p.top+p.height < len(p.lines)
+}
+
+// Next moves forward one screen. It returns false, without moving, if there
+// are no more lines.
+func (p *Pager) Next() bool {
+	if !p.More() {
+		return false
+	}
+	p.top += p.height
+	return true
+}
+
+// Prev moves back one screen, stopping at the first line. It returns false
+// if already at the first line.
+func (p *Pager) Prev() bool {
+	if p.top == 0 {
+		return false
+	}
+	p.top -= p.height
+	if p.top < 0 {
+		p.top = 0
+	}
+	return true
+}
+
+func (p *Pager) window(start int) []string {
+	end := start + p.height
+	if end > len(p.lines) {
+		end = len(p.lines)
+	}
+	return p.lines[start:end]
+}
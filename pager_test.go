@@ -0,0 +1,81 @@
+package ansitags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagerPages(t *testing.T) {
+	p := NewPager("one two three four five six sevens", 9, 2)
+
+	assert.Equal(t, 5, p.Lines())
+	assert.Equal(t, 3, p.Pages())
+	assert.Equal(t, []string{"one two", "three"}, p.Page(0))
+	assert.Equal(t, []string{"four five", "six"}, p.Page(1))
+	assert.Equal(t, []string{"sevens"}, p.Page(2))
+	assert.Nil(t, p.Page(3))
+	assert.Nil(t, p.Page(-1))
+}
+
+func TestPagerNavigation(t *testing.T) {
+	p := NewPager("a\nb\nc\nd\ne", 10, 2)
+
+	assert.Equal(t, []string{"a", "b"}, p.Current())
+	assert.True(t, p.More())
+	assert.False(t, p.Prev())
+
+	assert.True(t, p.Next())
+	assert.Equal(t, []string{"c", "d"}, p.Current())
+	assert.Equal(t, 1, p.PageNumber())
+
+	assert.True(t, p.Next())
+	assert.Equal(t, []string{"e"}, p.Current())
+	assert.False(t, p.More())
+	assert.False(t, p.Next())
+
+	assert.True(t, p.Prev())
+	assert.Equal(t, []string{"c", "d"}, p.Current())
+}
+
+func TestPagerSeek(t *testing.T) {
+	p := NewPager("a\nb\nc\nd\ne", 10, 2)
+
+	p.SeekLine(3)
+	assert.Equal(t, 3, p.Line())
+	assert.Equal(t, []string{"d", "e"}, p.Current())
+
+	// Going back from a line that isn't on a page boundary stops at the top
+	assert.True(t, p.Prev())
+	assert.Equal(t, []string{"b", "c"}, p.Current())
+	assert.True(t, p.Prev())
+	assert.Equal(t, 0, p.Line())
+
+	p.SeekPage(2)
+	assert.Equal(t, []string{"e"}, p.Current())
+
+	p.SeekPage(10)
+	assert.Equal(t, 2, p.PageNumber())
+	p.SeekLine(-4)
+	assert.Equal(t, 0, p.Line())
+}
+
+func TestPagerCarriesTags(t *testing.T) {
+	p := NewPager(`<ansi fg="red">one two <ansi bg="blue">three four</ansi> five</ansi> six`, 5, 2)
+
+	assert.Equal(t, []string{`<ansi fg="red">one</ansi>`, `<ansi fg="red">two</ansi>`}, p.Page(0))
+	assert.Equal(t, []string{`<ansi fg="red"><ansi bg="blue">three</ansi></ansi>`, `<ansi fg="red"><ansi bg="blue">four</ansi></ansi>`}, p.Page(1))
+	assert.Equal(t, []string{`<ansi fg="red">five</ansi>`, `six`}, p.Page(2))
+}
+
+func TestPagerWrapOptions(t *testing.T) {
+	p := NewPager("one two three", 7, 5, WrapOptions{Prefix: "> "})
+	assert.Equal(t, []string{"> one", "> two", "> three"}, p.Current())
+}
+
+func TestPagerEmpty(t *testing.T) {
+	p := NewPager("", 10, 0)
+	assert.Equal(t, 1, p.Pages())
+	assert.Equal(t, []string{""}, p.Current())
+	assert.False(t, p.Next())
+}
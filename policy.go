@@ -0,0 +1,108 @@
+package ansitags
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Policy lists the tag attributes, and the values of them, that a piece of
+// text is allowed to use. The zero Policy allows nothing.
+type Policy struct {
+	Fg       bool     // allow fg colors
+	Bg       bool     // allow bg colors
+	MinColor int      // lowest color number allowed for fg and bg
+	MaxColor int      // highest color number allowed for fg and bg, 0 for 255
+	Position bool     // allow position
+	Clear    []string // clear modes allowed, e.g. "aftercursor"
+}
+
+var (
+	// PolicyNone allows no attributes at all, so every tag has no effect.
+	PolicyNone = Policy{}
+	// PolicyColors allows fg and bg colors only, e.g. for player written text.
+	PolicyColors = Policy{Fg: true, Bg: true}
+	// PolicyAll allows every attribute, e.g. for admins and system messages.
+	PolicyAll = Policy{Fg: true, Bg: true, Position: true, Clear: []string{"aftercursor", "beforecursor", "all", "scrollback"}}
+)
+
+// Sanitize removes every tag attribute that policy doesn't allow from str,
+// leaving the tags themselves in place so the markup stays balanced. A tag
+// with nothing left in it becomes a bare <ansi>, which keeps the colors
+// around it. Attributes with values that aren't valid, or that fall outside
+// the policy, are removed as well. Text between tags is left as is.
+//
+// Usage:
+//
+// msg := ansitags.Sanitize(playerInput, ansitags.PolicyColors)
+// fmt.Println(ansitags.Parse(msg))
+func Sanitize(str string, policy Policy) string {
+
+	rwLock.RLock()
+	defer rwLock.RUnlock()
+
+	aliases := loadAliasSnapshot()
+
+	var out strings.Builder
+	out.Grow(len(str))
+
+	for _, tok := range tokenize(str) {
+		if tok.kind != tokenOpen {
+			out.WriteString(tok.raw)
+			continue
+		}
+
+		// Keep the text of each allowed attribute just as it was written
+		out.WriteString(tok.raw[:1+len(tagOpen)])
+		for i := 1 + len(tagOpen); i < len(tok.raw); {
+			key, val, next, ok := nextAttribute(tok.raw, i)
+			if !ok {
+				break
+			}
+			if policy.allows(key, val, aliases) {
+				out.WriteString(tok.raw[i:next])
+			}
+			i = next
+		}
+		out.WriteByte(tagEnd)
+	}
+
+	return out.String()
+}
+
+// allows reports whether the policy permits the attribute key=val.
+func (p Policy) allows(key string, val string, aliases map[string]int) bool {
+
+	if len(val) == 0 {
+		return false
+	}
+
+	switch key {
+	case "fg", "bg":
+		if (key == "fg" && !p.Fg) || (key == "bg" && !p.Bg) {
+			return false
+		}
+		num, err := strconv.Atoi(val)
+		if err != nil {
+			var ok bool
+			if num, ok = aliases[val]; !ok {
+				return false
+			}
+		}
+		maxColor := p.MaxColor
+		if maxColor == 0 {
+			maxColor = 255
+		}
+		return num >= 0 && num <= 255 && num >= p.MinColor && num <= maxColor
+	case "position":
+		return p.Position && validateAttribute(key, val, aliases) == ""
+	case "clear":
+		for _, mode := range p.Clear {
+			if mode == val {
+				_, ok := clearMap[val]
+				return ok
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,59 @@
+package ansitags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeColors(t *testing.T) {
+	input := `<ansi fg="red" clear=scrollback>hi <ansi position=1,1 bg=4>there</ansi></ansi>`
+	result := Sanitize(input, PolicyColors)
+
+	assert.Equal(t, `<ansi fg="red">hi <ansi bg=4>there</ansi></ansi>`, result)
+	assert.Nil(t, Validate(result))
+}
+
+func TestSanitizeNone(t *testing.T) {
+	result := Sanitize(`<ansi clear=all>a</ansi> <ansi fg=red>b</ansi>`, PolicyNone)
+	assert.Equal(t, `<ansi>a</ansi> <ansi>b</ansi>`, result)
+	assert.Equal(t, "\x1b[0ma\x1b[0m \x1b[0mb\x1b[0m", Parse(result))
+}
+
+func TestSanitizeAll(t *testing.T) {
+	input := `<ansi fg=red bg='blue' position="topleft" clear=all>x</ansi>`
+	assert.Equal(t, input, Sanitize(input, PolicyAll))
+}
+
+func TestSanitizeColorRange(t *testing.T) {
+	policy := Policy{Fg: true, MinColor: 1, MaxColor: 15}
+
+	assert.Equal(t, `<ansi fg=9>x</ansi>`, Sanitize(`<ansi fg=9>x</ansi>`, policy))
+	assert.Equal(t, `<ansi fg="red">x</ansi>`, Sanitize(`<ansi fg="red">x</ansi>`, policy))
+	assert.Equal(t, `<ansi>x</ansi>`, Sanitize(`<ansi fg=0>x</ansi>`, policy))
+	assert.Equal(t, `<ansi>x</ansi>`, Sanitize(`<ansi fg=200 bg=1>x</ansi>`, policy))
+	assert.Equal(t, `<ansi>x</ansi>`, Sanitize(`<ansi fg=mauve>x</ansi>`, policy))
+	assert.Equal(t, `<ansi>x</ansi>`, Sanitize(`<ansi fg=999>x</ansi>`, PolicyColors))
+}
+
+func TestSanitizeClearModes(t *testing.T) {
+	policy := Policy{Clear: []string{"aftercursor"}}
+
+	assert.Equal(t, `<ansi clear=aftercursor>`, Sanitize(`<ansi clear=aftercursor>`, policy))
+	assert.Equal(t, `<ansi>`, Sanitize(`<ansi clear=all>`, policy))
+	assert.Equal(t, `<ansi>`, Sanitize(`<ansi clear=bogus>`, Policy{Clear: []string{"bogus"}}))
+}
+
+func TestSanitizeKeepsText(t *testing.T) {
+	input := `1 < 2 <\ansi clear=all> </ansi <ansi clear=all`
+	assert.Equal(t, input, Sanitize(input, PolicyNone))
+
+	// Parse reads anything starting <ansi as a tag
+	assert.Equal(t, `<ansi>x</ansi>`, Sanitize(`<ansin't clear=all>x</ansi>`, PolicyNone))
+}
+
+func TestSanitizeQuotedValues(t *testing.T) {
+	// A value can't smuggle in another attribute
+	result := Sanitize(`<ansi fg="red clear=all" bg=1>x</ansi>`, PolicyColors)
+	assert.Equal(t, `<ansi bg=1>x</ansi>`, result)
+}
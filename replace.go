@@ -0,0 +1,97 @@
+package ansitags
+
+import "strings"
+
+// ReplaceVisible replaces every match of pattern in the visible text of a
+// tagged string with replacement. Matching ignores tags, as with Highlight.
+// Any tags inside a match are kept, just after the replacement, so the
+// markup stays balanced. The replacement is inserted as is, so it may carry
+// its own tags.
+//
+// Usage:
+//
+// ansitags.ReplaceVisible(chatLine, ansitags.Literal("Bob"), `<ansi fg="cyan">Robert</ansi>`)
+func ReplaceVisible(str string, pattern Matcher, replacement string) string {
+	return rewriteMatches(str, pattern, func(match string, start bool) string {
+		if start {
+			return replacement
+		}
+		return ""
+	})
+}
+
+// Redact masks every match of pattern in the visible text of a tagged string
+// with mask, one per column the matched text takes up, so the line keeps its
+// width. Tags inside a match are left in place.
+//
+// Usage:
+//
+// ansitags.Redact(logLine, regexp.MustCompile(`password=\S+`), '*')
+func Redact(str string, pattern Matcher, mask rune) string {
+	return rewriteMatches(str, pattern, func(match string, start bool) string {
+		return padding(textWidth(match, 0), mask)
+	})
+}
+
+// rewriteMatches copies str, passing the visible text of each match of
+// pattern through rewrite and keeping every tag. A match split by tags is
+// passed in pieces, with start set for the first piece. A < right before a
+// piece is escaped, so what replaces the piece can't finish a tag with it.
+func rewriteMatches(str string, pattern Matcher, rewrite func(match string, start bool) string) string {
+
+	tokens := tokenize(str)
+	spans := visibleMatches(tokens, pattern)
+	if len(spans) == 0 {
+		return str
+	}
+
+	var out strings.Builder
+	out.Grow(len(str))
+
+	offset := 0 // offset into the visible text
+	next := 0   // index of the next span
+
+	for _, tok := range tokens {
+		if tok.kind != tokenText {
+			out.WriteString(tok.raw)
+			continue
+		}
+
+		text := tok.raw
+		for len(text) > 0 {
+			if next >= len(spans) {
+				out.WriteString(text)
+				offset += len(text)
+				break
+			}
+
+			span := spans[next]
+
+			if offset < span[0] {
+				n := span[0] - offset
+				if n > len(text) {
+					n = len(text)
+				}
+				out.WriteString(text[:n])
+				text = text[n:]
+				offset += n
+				continue
+			}
+
+			n := span[1] - offset
+			if n > len(text) {
+				n = len(text)
+			}
+			escapeTagStartBefore(&out)
+			out.WriteString(rewrite(text[:n], offset == span[0]))
+			text = text[n:]
+			offset += n
+
+			if offset == span[1] {
+				next++
+			}
+		}
+	}
+
+	return out.String()
+}
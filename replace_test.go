@@ -0,0 +1,91 @@
+package ansitags
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceVisible(t *testing.T) {
+	assert.Equal(t, "Hello Robert, bye Robert", ReplaceVisible("Hello Bob, bye Bob", Literal("Bob"), "Robert"))
+	assert.Equal(t, "no match", ReplaceVisible("no match", Literal("Bob"), "Robert"))
+	assert.Equal(t, "Hello ", ReplaceVisible("Hello Bob", Literal("Bob"), ""))
+}
+
+func TestReplaceVisibleKeepsTags(t *testing.T) {
+	input := `<ansi fg="red">Bob</ansi> says <ansi fg="green">hi</ansi>`
+
+	assert.Equal(t, `<ansi fg="red">Robert</ansi> says <ansi fg="green">hi</ansi>`, ReplaceVisible(input, Literal("Bob"), "Robert"))
+
+	// Tags inside a match are kept after the replacement, which goes where the match started
+	assert.Equal(t, `<ansi fg="red">Bo[bye]</ansi><ansi fg="green"></ansi>`, ReplaceVisible(input, Literal("b says hi"), "[bye]"))
+
+	// Attribute values are never replaced
+	assert.Equal(t, `<ansi fg="red">Bob</ansi> says <ansi fg="green">hello</ansi>`, ReplaceVisible(input, regexp.MustCompile(`\bhi\b|green`), "hello"))
+}
+
+func TestReplaceVisibleTaggedReplacement(t *testing.T) {
+	result := ReplaceVisible("Bob waves.", Literal("Bob"), `<ansi fg="cyan">Bob</ansi>`)
+	assert.Equal(t, `<ansi fg="cyan">Bob</ansi> waves.`, result)
+}
+
+func TestRedact(t *testing.T) {
+	re := regexp.MustCompile(`password=\S+`)
+
+	assert.Equal(t, "login **************** ok", Redact("login password=hunter2 ok", re, '*'))
+	assert.Equal(t, `<ansi fg="red">login</ansi> <ansi fg="yellow">*********</ansi>****** ok`,
+		Redact(`<ansi fg="red">login</ansi> <ansi fg="yellow">password=</ansi>hunter ok`, re, '*'))
+}
+
+func TestRedactEscaped(t *testing.T) {
+	// An escaped < is a single column of the printed text
+	assert.Equal(t, "a ** b", Redact(`a <\3 b`, Literal("<3"), '*'))
+	assert.Equal(t, "a love b", ReplaceVisible(`a <\3 b`, Literal("<3"), "love"))
+}
+
+func TestReplaceCantFormTags(t *testing.T) {
+	inputs := []string{
+		"<darnansi clear=all>hi",
+		"<darn/ansi>hi",
+		`<ansi fg="red"><darn</ansi>ansi clear=all>`,
+		"<darn<ansi fg=red>x</ansi>",
+	}
+
+	for _, input := range inputs {
+		for _, result := range []string{
+			ReplaceVisible(input, Literal("darn"), ""),
+			ReplaceVisible(input, Literal("darn"), "a"),
+			Redact(input, Literal("darn"), '<'),
+			Redact(input, Literal("darn"), '*'),
+		} {
+			assert.LessOrEqual(t, countTags(result), countTags(input), "%q -> %q", input, result)
+			assert.NotContains(t, Parse(result), "\x1b[2J", "%q -> %q", input, result)
+		}
+	}
+
+	result := ReplaceVisible("<darnansi clear=all>hi", Literal("darn"), "")
+	assert.Equal(t, `<\ansi clear=all>hi`, result)
+	assert.Equal(t, "<ansi clear=all>hi", Parse(result))
+}
+
+// countTags returns the number of open and close tags Parse finds in str.
+func countTags(str string) int {
+	count := 0
+	for _, tok := range tokenize(str) {
+		if tok.kind != tokenText {
+			count++
+		}
+	}
+	return count
+}
+
+func TestRedactWidth(t *testing.T) {
+	// Wide characters are masked once per column
+	assert.Equal(t, "秘密 is ####", Redact("秘密 is 秘密", regexp.MustCompile(`秘密$`), '#'))
+
+	input := "darn it, darn"
+	result := Redact(input, Literal("darn"), '█')
+	assert.Equal(t, "████ it, ████", result)
+	assert.Equal(t, VisibleWidth(input), VisibleWidth(result))
+}
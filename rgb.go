@@ -1,5 +1,7 @@
 package ansitags
 
+import "strconv"
+
 // Accepts a 4-bit or 8-bit ANSI color code and returns an rgb struct for it.
 // Usage:
 //
@@ -28,6 +30,24 @@ func RGB(colorCode int) rgb {
 	return ansi256[colorCode]
 }
 
+// nearestColor returns the color code below limit whose palette entry is
+// closest to r, g, b.
+func nearestColor(r, g, b uint8, limit int) int {
+	best := 0
+	bestDist := -1
+	for i := 0; i < limit && i < 256; i++ {
+		clr := ansi256[i]
+		dr := int(clr.R) - int(r)
+		dg := int(clr.G) - int(g)
+		db := int(clr.B) - int(b)
+		dist := dr*dr + dg*dg + db*db
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
+
 // newRGB constructs an rgb and pre‐computes its hex string.
 func newRGB(r, g, b uint8) rgb {
 	const hexdigits = "0123456789abcdef"
@@ -88,4 +108,24 @@ func init() {
 		htmlFgStyle[i] = "color:#" + clr.Hex + ";"
 		htmlBgStyle[i] = "background-color:#" + clr.Hex + ";"
 	}
+
+	// Pre-compute the escape sequences used for the 16 color and 24 bit color depths.
+	for i := 0; i < 256; i++ {
+		clr := ansi256[i]
+		rgbParams := strconv.Itoa(int(clr.R)) + ";" + strconv.Itoa(int(clr.G)) + ";" + strconv.Itoa(int(clr.B)) + "m"
+		ansi24FgSeq[i] = "\033[38;2;" + rgbParams
+		ansi24BgSeq[i] = "\033[48;2;" + rgbParams
+
+		basic := i
+		if basic > 15 {
+			basic = nearestColor(clr.R, clr.G, clr.B, 16)
+		}
+		if basic < 8 {
+			ansi16FgSeq[i] = "\033[" + strconv.Itoa(30+basic) + "m"
+			ansi16BgSeq[i] = "\033[" + strconv.Itoa(40+basic) + "m"
+		} else {
+			ansi16FgSeq[i] = "\033[" + strconv.Itoa(90+basic-8) + "m"
+			ansi16BgSeq[i] = "\033[" + strconv.Itoa(100+basic-8) + "m"
+		}
+	}
 }
@@ -0,0 +1,99 @@
+package ansitags
+
+// Run is a span of visible text that shares a single, fully resolved style.
+// Colors left unset by the tags (terminal default) are nil and are omitted
+// from the JSON encoding.
+//
+// Usage:
+//
+// b, _ := json.Marshal(ansitags.Runs(`Hi <ansi fg="red">there</ansi>`))
+// fmt.Println(string(b))
+//
+// Output:
+// [{"text":"Hi "},{"text":"there","fg":{"index":1,"hex":"800000"}}]
+type Run struct {
+	Text string `json:"text"`
+	Fg   *Color `json:"fg,omitempty"`
+	Bg   *Color `json:"bg,omitempty"`
+}
+
+// Color is a resolved entry of the 256 color palette.
+type Color struct {
+	Index int    `json:"index"`
+	Hex   string `json:"hex"`
+}
+
+// Runs breaks a tagged string into styled runs of visible text. Tags are
+// resolved exactly as Parse resolves them, so rendering each run with its
+// colors looks the same as printing the parsed string.
+func Runs(str string) []Run {
+
+	rwLock.RLock()
+	defer rwLock.RUnlock()
+
+	var runs []Run
+	var tagStack []*ansiProperties = make([]*ansiProperties, 0, 5)
+
+	for _, tok := range tokenize(str) {
+		switch tok.kind {
+		case tokenOpen:
+			tagStack = append(tagStack, extractProperties(tok.raw))
+		case tokenClose:
+			if stackLen := len(tagStack); stackLen > 0 {
+				releaseProperties(tagStack[stackLen-1])
+				tagStack[stackLen-1] = nil
+				tagStack = tagStack[:stackLen-1]
+			}
+		default:
+			fg, bg := stackColors(tagStack)
+			runs = appendRun(runs, unescape(tok.raw), newColor(fg), newColor(bg))
+		}
+	}
+
+	for _, p := range tagStack {
+		releaseProperties(p)
+	}
+
+	return runs
+}
+
+// stackColors returns the fg and bg in effect at the top of a tag stack.
+func stackColors(tagStack []*ansiProperties) (fg int, bg int) {
+	stackLen := len(tagStack)
+	if stackLen == 0 {
+		return defaultFg256, defaultBg256
+	}
+	if stackLen == 1 {
+		return tagStack[0].resolveColors(nil)
+	}
+	return tagStack[stackLen-1].resolveColors(tagStack[stackLen-2])
+}
+
+// newColor returns the palette entry for colorCode, or nil if the code does
+// not refer to a palette color (e.g. the terminal default).
+func newColor(colorCode int) *Color {
+	if colorCode < 0 || colorCode > 255 {
+		return nil
+	}
+	return &Color{Index: colorCode, Hex: ansi256[colorCode].Hex}
+}
+
+// appendRun adds text to runs, merging it into the last run when the style
+// is unchanged.
+func appendRun(runs []Run, text string, fg *Color, bg *Color) []Run {
+	if len(text) == 0 {
+		return runs
+	}
+	if last := len(runs) - 1; last >= 0 && sameColor(runs[last].Fg, fg) && sameColor(runs[last].Bg, bg) {
+		runs[last].Text += text
+		return runs
+	}
+	return append(runs, Run{Text: text, Fg: fg, Bg: bg})
+}
+
+func sameColor(a *Color, b *Color) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Index == b.Index
+}
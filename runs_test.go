@@ -0,0 +1,68 @@
+package ansitags
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsPlainText(t *testing.T) {
+	assert.Equal(t, []Run{{Text: "Just text"}}, Runs("Just text"))
+	assert.Nil(t, Runs(""))
+	assert.Nil(t, Runs(`<ansi fg="red"></ansi>`))
+}
+
+func TestRunsResolvesColors(t *testing.T) {
+	result := Runs(`Hi <ansi fg="red" bg=4>there</ansi> you`)
+
+	assert.Equal(t, []Run{
+		{Text: "Hi "},
+		{Text: "there", Fg: &Color{Index: 1, Hex: "800000"}, Bg: &Color{Index: 4, Hex: "000080"}},
+		{Text: " you"},
+	}, result)
+}
+
+func TestRunsInheritsFromParent(t *testing.T) {
+	result := Runs(`<ansi fg="green" bg="blue">a<ansi fg="red">b</ansi>c</ansi>`)
+
+	assert.Equal(t, 3, len(result))
+	assert.Equal(t, "b", result[1].Text)
+	assert.Equal(t, 1, result[1].Fg.Index)
+	assert.Equal(t, 4, result[1].Bg.Index)
+	assert.Equal(t, 2, result[2].Fg.Index)
+}
+
+func TestRunsMergesIdenticalStyles(t *testing.T) {
+	result := Runs(`<ansi fg="red">a</ansi><ansi fg="1">b</ansi>c<ansi bg="blue"></ansi>d`)
+
+	assert.Equal(t, []Run{
+		{Text: "ab", Fg: &Color{Index: 1, Hex: "800000"}},
+		{Text: "cd"},
+	}, result)
+}
+
+func TestRunsKeepsNonTagText(t *testing.T) {
+	result := Runs(`1 < 2 <ansin't> <ansi fg=red>x</ansi`)
+
+	var all string
+	for _, r := range result {
+		all += r.Text
+	}
+	assert.Equal(t, "1 < 2  x</ansi", all)
+}
+
+func TestRunsUnescapes(t *testing.T) {
+	result := Runs(`<ansi fg="red">x <\ y</ansi> <\\`)
+	assert.Equal(t, []Run{
+		{Text: "x < y", Fg: &Color{Index: 1, Hex: "800000"}},
+		{Text: ` <\`},
+	}, result)
+}
+
+func TestRunsJSON(t *testing.T) {
+	b, err := json.Marshal(Runs(`Hi <ansi fg="red">there</ansi>`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"text":"Hi "},{"text":"there","fg":{"index":1,"hex":"800000"}}]`, string(b))
+}
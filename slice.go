@@ -0,0 +1,93 @@
+package ansitags
+
+import "strings"
+
+// Slice returns the part of a tagged string covering visible columns
+// [start, end), with the tags that are open at start reopened and any still
+// open at end closed, so the result can be parsed on its own. Columns are
+// counted the same way as VisibleWidth. Wide characters that straddle start
+// or end are left out.
+//
+// Usage:
+//
+// // Scroll a 20 column marquee one column per tick
+// fmt.Println(ansitags.Parse(ansitags.Slice(banner, tick, tick+20)))
+func Slice(str string, start int, end int) string {
+
+	if start < 0 {
+		start = 0
+	}
+	if end <= start {
+		return ""
+	}
+
+	var out strings.Builder
+	var stack []string
+
+	started := false
+	col := 0     // columns advanced across the whole string
+	lineCol := 0 // column within the current line, for tab stops
+
+	for _, tok := range tokenize(str) {
+		switch tok.kind {
+		case tokenOpen:
+			if started && col >= end {
+				return closeSlice(&out, stack)
+			}
+			stack = append(stack, tok.raw)
+			if started {
+				out.WriteString(tok.raw)
+			}
+			continue
+		case tokenClose:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+				if started {
+					out.WriteString(tok.raw)
+				}
+			}
+			continue
+		}
+
+		for i := 0; i < len(tok.raw); {
+			size := clusterSize(tok.raw[i:])
+			cluster := tok.raw[i : i+size]
+			i += size
+
+			width := clusterWidth(cluster, lineCol)
+			if cluster[len(cluster)-1] == '\n' { // \n or \r\n
+				lineCol = 0
+			} else {
+				lineCol += width
+			}
+
+			if col+width > end || (width == 0 && col >= end) {
+				return closeSlice(&out, stack)
+			}
+
+			if col >= start {
+				if !started {
+					started = true
+					for _, tag := range stack {
+						out.WriteString(tag)
+					}
+				}
+				out.WriteString(cluster)
+			}
+			col += width
+		}
+	}
+
+	return closeSlice(&out, stack)
+}
+
+// closeSlice closes the tags left open in a slice and returns it.
+func closeSlice(out *strings.Builder, stack []string) string {
+	if out.Len() == 0 {
+		return ""
+	}
+	for range stack {
+		out.WriteString("</ansi>")
+	}
+	return out.String()
+}
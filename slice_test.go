@@ -0,0 +1,62 @@
+package ansitags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlice(t *testing.T) {
+	input := `<ansi fg="red">AB</ansi>CD<ansi fg="blue">EF</ansi>`
+
+	assert.Equal(t, `<ansi fg="red">AB</ansi>`, Slice(input, 0, 2))
+	assert.Equal(t, `CD`, Slice(input, 2, 4))
+	assert.Equal(t, `<ansi fg="red">B</ansi>C`, Slice(input, 1, 3))
+	assert.Equal(t, `D<ansi fg="blue">E</ansi>`, Slice(input, 3, 5))
+	assert.Equal(t, `<ansi fg="blue">F</ansi>`, Slice(input, 5, 10))
+	assert.Equal(t, input, Slice(input, 0, 6))
+}
+
+func TestSliceTabAfterCRLF(t *testing.T) {
+	// The tab starts a new line, so it only takes up a full tab stop
+	assert.Equal(t, "ab\r\n\t", Slice("ab\r\n\tx", 0, 10))
+}
+
+func TestSliceEmpty(t *testing.T) {
+	input := `<ansi fg="red">AB</ansi>CD`
+
+	assert.Equal(t, ``, Slice(input, 4, 10))
+	assert.Equal(t, ``, Slice(input, 3, 3))
+	assert.Equal(t, ``, Slice(input, 3, 1))
+	assert.Equal(t, `<ansi fg="red">AB</ansi>`, Slice(input, -5, 2))
+}
+
+func TestSliceNested(t *testing.T) {
+	input := `<ansi fg="yellow">one <ansi bg="blue">two</ansi> three</ansi>`
+
+	assert.Equal(t, `<ansi fg="yellow"><ansi bg="blue">wo</ansi> t</ansi>`, Slice(input, 5, 9))
+	assert.Equal(t, `<ansi fg="yellow"><ansi bg="blue">w</ansi></ansi>`, Slice(input, 5, 6))
+	assert.Equal(t, `<ansi fg="yellow">e <ansi bg="blue">t</ansi></ansi>`, Slice(input, 2, 5))
+}
+
+func TestSliceWide(t *testing.T) {
+	// Wide characters straddling either edge are left out
+	assert.Equal(t, "本", Slice("日本語", 1, 5))
+	assert.Equal(t, "日本", Slice("日本語", 0, 5))
+	assert.Equal(t, "éf", Slice("défg", 1, 3))
+}
+
+func TestSliceMarquee(t *testing.T) {
+	input := `<ansi fg="red">Welcome</ansi> to <ansi fg="cyan">the realm</ansi>`
+	total := VisibleWidth(input)
+
+	for start := 0; start < total; start++ {
+		part := Slice(input, start, start+5)
+		expected := 5
+		if total-start < expected {
+			expected = total - start
+		}
+		assert.Equal(t, expected, VisibleWidth(part), "start=%d part=%q", start, part)
+		assert.Equal(t, Parse(Slice(Parse(input, StripTags), start, start+5)), Parse(part, StripTags), "start=%d", start)
+	}
+}
@@ -2,6 +2,10 @@ package ansitags
 
 import "strings"
 
+// SplitString splits input into segments of at most maxLen visible columns,
+// carrying any open tags over into the next segment so that each segment can
+// be parsed on its own. Wide characters that would straddle the limit are
+// moved to the next segment.
 func SplitString(input string, maxLen int, trimSpace ...bool) []string {
 	doTrim := true
 	if len(trimSpace) > 0 {
@@ -12,276 +16,91 @@ func SplitString(input string, maxLen int, trimSpace ...bool) []string {
 		return []string{input}
 	}
 
-	totalVisible := visibleLen(input)
-	if totalVisible <= maxLen {
+	if visibleLen(input) <= maxLen {
 		return []string{input}
 	}
 
-	var result []string
-	var tagStack []string
-	var current strings.Builder
-	visibleCount := 0
-	totalConsumed := 0
-
-	openMatcher := NewTagMatcher(tagStart, []byte(tagOpen), tagEnd, true)
-	closeMatcher := NewTagMatcher(tagStart, []byte(tagClose), tagEnd, false)
-
-	var tagBuf [maxTagSize]byte
-	var tagLen int
-	var mode parseMode = parseModeNone
-
-	split := func() {
-		for j := len(tagStack) - 1; j >= 0; j-- {
-			current.WriteString("</ansi>")
-		}
-		result = append(result, current.String())
-		current.Reset()
-		for _, tag := range tagStack {
-			current.WriteString(tag)
-		}
-		visibleCount = 0
-	}
-
-	writeVisible := func(b byte) {
-		current.WriteByte(b)
-		visibleCount++
-		totalConsumed++
-		if visibleCount >= maxLen && totalConsumed < totalVisible {
-			split()
-		}
-	}
-
-	for i := 0; i < len(input); i++ {
-		ch := input[i]
+	tokens := tokenize(input)
+	points := splitPoints(tokens, maxLen, false)
 
-		if mode == parseModeNone {
-			if ch != tagStart {
-				writeVisible(ch)
-				continue
-			}
-			mode = parseModeMatching
-		}
-
-		if mode == parseModeMatching {
-			openMatch, openMatchDone := openMatcher.MatchNext(ch)
-			closeMatch, closeMatchDone := closeMatcher.MatchNext(ch)
-
-			if openMatch {
-				if tagLen < maxTagSize {
-					tagBuf[tagLen] = ch
-					tagLen++
-				}
-				if !openMatchDone {
-					continue
-				}
-				tagStr := string(tagBuf[:tagLen])
-				tagStack = append(tagStack, tagStr)
-				current.WriteString(tagStr)
-				tagLen = 0
-				mode = parseModeNone
-				openMatcher.Reset()
-				closeMatcher.Reset()
-				continue
-			}
-			openMatcher.Reset()
-
-			if closeMatch {
-				if tagLen < maxTagSize {
-					tagBuf[tagLen] = ch
-					tagLen++
-				}
-				if !closeMatchDone {
-					continue
-				}
-				tagLen = 0
-				if len(tagStack) > 0 {
-					tagStack = tagStack[:len(tagStack)-1]
-				}
-				current.WriteString("</ansi>")
-				mode = parseModeNone
-				openMatcher.Reset()
-				closeMatcher.Reset()
-				continue
-			}
-			closeMatcher.Reset()
-
-			if closeMatchDone && openMatchDone {
-				if tagLen < maxTagSize {
-					tagBuf[tagLen] = ch
-					tagLen++
-				}
-			}
-
-			mode = parseModeNone
+	return splitAtPoints(tokens, points, doTrim)
+}
 
-			for j := 0; j < tagLen; j++ {
-				writeVisible(tagBuf[j])
-			}
-			tagLen = 0
+// splitPoints computes the visible cluster counts at which to split, so that
+// no segment is wider than maxLen columns. The split occurs after the cluster
+// at that count (inclusive). If onSpaces is set a split prefers to land just
+// after the last space before the limit, falling back to a split at the limit
+// if there is none.
+func splitPoints(tokens []token, maxLen int, onSpaces bool) []int {
+
+	var clusters []string
+	lastVisible := -1
+	for _, tok := range tokens {
+		if tok.kind != tokenText {
 			continue
 		}
-	}
-
-	if tagLen > 0 {
-		for j := 0; j < tagLen; j++ {
-			writeVisible(tagBuf[j])
-		}
-	}
-
-	if current.Len() > 0 {
-		result = append(result, current.String())
-	}
-
-	if len(result) == 0 {
-		return []string{input}
-	}
-
-	if doTrim {
-		for i, seg := range result {
-			result[i] = trimTagAwareSpaces(seg)
+		for i := 0; i < len(tok.raw); {
+			size := clusterSize(tok.raw[i:])
+			if clusterWidth(tok.raw[i:i+size], 1) > 0 {
+				lastVisible = len(clusters)
+			}
+			clusters = append(clusters, tok.raw[i:i+size])
+			i += size
 		}
 	}
 
-	return result
-}
-
-// splitPoints computes the 1-based visible-character counts at which to split,
-// preferring a space at or before each maxLen boundary. The split occurs after
-// the character at that count (inclusive). If no space is found, falls back to
-// a character-based split at the maxLen boundary.
-func splitPoints(input string, maxLen int) []int {
-	openMatcher := NewTagMatcher(tagStart, []byte(tagOpen), tagEnd, true)
-	closeMatcher := NewTagMatcher(tagStart, []byte(tagClose), tagEnd, false)
-	var tagBuf [maxTagSize]byte
-	var tagLen int
-	var mode parseMode = parseModeNone
-
-	// consumed is 1-based: the count of visible chars seen so far.
-	consumed := 0
-	totalVisible := visibleLen(input)
-
 	var points []int
-	nextTarget := maxLen
-	// lastSpaceAt is the 1-based consumed value just after the last seen space.
+	// col is the width of the current segment, which starts after the last point.
+	col := 0
+	// lastSpaceAt is the cluster count just after the last space in the segment.
 	lastSpaceAt := -1
 
-	recordVisible := func(ch byte) {
-		consumed++
-		if ch == ' ' {
-			lastSpaceAt = consumed
+	split := func(count int) {
+		splitAt := count
+		if lastSpaceAt > 0 {
+			splitAt = lastSpaceAt
 		}
-		for consumed >= nextTarget && consumed < totalVisible {
-			var splitAt int
-			if lastSpaceAt > 0 {
-				splitAt = lastSpaceAt
-				nextTarget = lastSpaceAt + maxLen
-			} else {
-				splitAt = nextTarget
-				nextTarget = nextTarget + maxLen
-			}
-			lastSpaceAt = -1
-			points = append(points, splitAt)
+		points = append(points, splitAt)
+		lastSpaceAt = -1
+
+		col = 0
+		for _, cluster := range clusters[splitAt:count] {
+			col += clusterWidth(cluster, col)
 		}
 	}
 
-	for i := 0; i < len(input); i++ {
-		ch := input[i]
-
-		if mode == parseModeNone {
-			if ch != tagStart {
-				recordVisible(ch)
-				continue
-			}
-			mode = parseModeMatching
+	for i, cluster := range clusters {
+		width := clusterWidth(cluster, col)
+		for col > 0 && col+width > maxLen {
+			// A wide character that doesn't fit goes to the next segment
+			split(i)
+			width = clusterWidth(cluster, col)
 		}
 
-		if mode == parseModeMatching {
-			openMatch, openMatchDone := openMatcher.MatchNext(ch)
-			closeMatch, closeMatchDone := closeMatcher.MatchNext(ch)
-
-			if openMatch {
-				if tagLen < maxTagSize {
-					tagBuf[tagLen] = ch
-					tagLen++
-				}
-				if !openMatchDone {
-					continue
-				}
-				tagLen = 0
-				mode = parseModeNone
-				openMatcher.Reset()
-				closeMatcher.Reset()
-				continue
-			}
-			openMatcher.Reset()
-
-			if closeMatch {
-				if tagLen < maxTagSize {
-					tagBuf[tagLen] = ch
-					tagLen++
-				}
-				if !closeMatchDone {
-					continue
-				}
-				tagLen = 0
-				mode = parseModeNone
-				openMatcher.Reset()
-				closeMatcher.Reset()
-				continue
-			}
-			closeMatcher.Reset()
+		col += width
+		if onSpaces && cluster == " " {
+			lastSpaceAt = i + 1
+		}
 
-			if closeMatchDone && openMatchDone {
-				if tagLen < maxTagSize {
-					tagBuf[tagLen] = ch
-					tagLen++
-				}
-			}
-			mode = parseModeNone
-			for j := 0; j < tagLen; j++ {
-				recordVisible(tagBuf[j])
-			}
-			tagLen = 0
-			continue
+		if col >= maxLen && i < lastVisible {
+			split(i + 1)
 		}
 	}
+
 	return points
 }
 
-// SplitStringOnSpaces splits input into segments of at most maxLen visible
-// characters, preferring to split at a space boundary. If no space exists
-// at or before the limit, it falls back to a character-based split.
-func SplitStringOnSpaces(input string, maxLen int, trimSpace ...bool) []string {
-	doTrim := true
-	if len(trimSpace) > 0 {
-		doTrim = trimSpace[0]
-	}
-
-	if maxLen <= 0 || len(input) == 0 {
-		return []string{input}
-	}
-
-	totalVisible := visibleLen(input)
-	if totalVisible <= maxLen {
-		return []string{input}
-	}
-
-	points := splitPoints(input, maxLen)
+// splitAtPoints cuts the tokens after each visible cluster count in points,
+// closing open tags at the end of a segment and reopening them at the start
+// of the next.
+func splitAtPoints(tokens []token, points []int, doTrim bool) []string {
 
 	var result []string
 	var tagStack []string
 	var current strings.Builder
-	visibleCount := 0
-	totalConsumed := 0
+	consumed := 0
 	pointIdx := 0
 
-	openMatcher := NewTagMatcher(tagStart, []byte(tagOpen), tagEnd, true)
-	closeMatcher := NewTagMatcher(tagStart, []byte(tagClose), tagEnd, false)
-
-	var tagBuf [maxTagSize]byte
-	var tagLen int
-	var mode parseMode = parseModeNone
-
 	split := func() {
 		for j := len(tagStack) - 1; j >= 0; j-- {
 			current.WriteString("</ansi>")
@@ -291,93 +110,29 @@ func SplitStringOnSpaces(input string, maxLen int, trimSpace ...bool) []string {
 		for _, tag := range tagStack {
 			current.WriteString(tag)
 		}
-		visibleCount = 0
 	}
 
-	writeVisible := func(b byte) {
-		current.WriteByte(b)
-		visibleCount++
-		totalConsumed++
-		if pointIdx < len(points) && totalConsumed == points[pointIdx] && totalConsumed < totalVisible {
-			pointIdx++
-			split()
-		}
-	}
-
-	for i := 0; i < len(input); i++ {
-		ch := input[i]
-
-		if mode == parseModeNone {
-			if ch != tagStart {
-				writeVisible(ch)
-				continue
+	for _, tok := range tokens {
+		switch tok.kind {
+		case tokenOpen:
+			tagStack = append(tagStack, tok.raw)
+			current.WriteString(tok.raw)
+		case tokenClose:
+			if len(tagStack) > 0 {
+				tagStack = tagStack[:len(tagStack)-1]
 			}
-			mode = parseModeMatching
-		}
-
-		if mode == parseModeMatching {
-			openMatch, openMatchDone := openMatcher.MatchNext(ch)
-			closeMatch, closeMatchDone := closeMatcher.MatchNext(ch)
-
-			if openMatch {
-				if tagLen < maxTagSize {
-					tagBuf[tagLen] = ch
-					tagLen++
+			current.WriteString(tok.raw)
+		default:
+			for i := 0; i < len(tok.raw); {
+				size := clusterSize(tok.raw[i:])
+				current.WriteString(tok.raw[i : i+size])
+				i += size
+				consumed++
+				if pointIdx < len(points) && consumed == points[pointIdx] {
+					pointIdx++
+					split()
 				}
-				if !openMatchDone {
-					continue
-				}
-				tagStr := string(tagBuf[:tagLen])
-				tagStack = append(tagStack, tagStr)
-				current.WriteString(tagStr)
-				tagLen = 0
-				mode = parseModeNone
-				openMatcher.Reset()
-				closeMatcher.Reset()
-				continue
 			}
-			openMatcher.Reset()
-
-			if closeMatch {
-				if tagLen < maxTagSize {
-					tagBuf[tagLen] = ch
-					tagLen++
-				}
-				if !closeMatchDone {
-					continue
-				}
-				tagLen = 0
-				if len(tagStack) > 0 {
-					tagStack = tagStack[:len(tagStack)-1]
-				}
-				current.WriteString("</ansi>")
-				mode = parseModeNone
-				openMatcher.Reset()
-				closeMatcher.Reset()
-				continue
-			}
-			closeMatcher.Reset()
-
-			if closeMatchDone && openMatchDone {
-				if tagLen < maxTagSize {
-					tagBuf[tagLen] = ch
-					tagLen++
-				}
-			}
-
-			mode = parseModeNone
-
-			for j := 0; j < tagLen; j++ {
-				writeVisible(tagBuf[j])
-			}
-			tagLen = 0
-			continue
-		}
-	}
-
-	if tagLen > 0 {
-		for j := 0; j < tagLen; j++ {
-			writeVisible(tagBuf[j])
 		}
 	}
 
@@ -385,10 +140,6 @@ func SplitStringOnSpaces(input string, maxLen int, trimSpace ...bool) []string {
 		result = append(result, current.String())
 	}
 
-	if len(result) == 0 {
-		return []string{input}
-	}
-
 	if doTrim {
 		for i, seg := range result {
 			result[i] = trimTagAwareSpaces(seg)
@@ -398,6 +149,29 @@ func SplitStringOnSpaces(input string, maxLen int, trimSpace ...bool) []string {
 	return result
 }
 
+// SplitStringOnSpaces splits input into segments of at most maxLen visible
+// columns, preferring to split at a space boundary. If no space exists
+// at or before the limit, it falls back to a character-based split.
+func SplitStringOnSpaces(input string, maxLen int, trimSpace ...bool) []string {
+	doTrim := true
+	if len(trimSpace) > 0 {
+		doTrim = trimSpace[0]
+	}
+
+	if maxLen <= 0 || len(input) == 0 {
+		return []string{input}
+	}
+
+	if visibleLen(input) <= maxLen {
+		return []string{input}
+	}
+
+	tokens := tokenize(input)
+	points := splitPoints(tokens, maxLen, true)
+
+	return splitAtPoints(tokens, points, doTrim)
+}
+
 func trimTagAwareSpaces(input string) string {
 	n := len(input)
 	if n == 0 {
@@ -491,58 +265,25 @@ func trimTagAwareSpaces(input string) string {
 	return buf.String()
 }
 
+// visibleLen returns the total number of columns input advances across all
+// of its lines, ignoring tags.
 func visibleLen(input string) int {
 	count := 0
-	openMatcher := NewTagMatcher(tagStart, []byte(tagOpen), tagEnd, true)
-	closeMatcher := NewTagMatcher(tagStart, []byte(tagClose), tagEnd, false)
-	var tagLen int
-	var mode parseMode = parseModeNone
-
-	for i := 0; i < len(input); i++ {
-		ch := input[i]
-		if mode == parseModeNone {
-			if ch != tagStart {
-				count++
-				continue
-			}
-			mode = parseModeMatching
+	col := 0
+	for _, tok := range tokenize(input) {
+		if tok.kind != tokenText {
+			continue
 		}
-		if mode == parseModeMatching {
-			openMatch, openMatchDone := openMatcher.MatchNext(ch)
-			closeMatch, closeMatchDone := closeMatcher.MatchNext(ch)
-			if openMatch {
-				tagLen++
-				if openMatchDone {
-					tagLen = 0
-					mode = parseModeNone
-					openMatcher.Reset()
-					closeMatcher.Reset()
-				}
-				continue
+		for i := 0; i < len(tok.raw); {
+			size := clusterSize(tok.raw[i:])
+			width := clusterWidth(tok.raw[i:i+size], col)
+			col += width
+			count += width
+			if tok.raw[i+size-1] == '\n' { // \n or \r\n
+				col = 0
 			}
-			openMatcher.Reset()
-			if closeMatch {
-				tagLen++
-				if closeMatchDone {
-					tagLen = 0
-					mode = parseModeNone
-					openMatcher.Reset()
-					closeMatcher.Reset()
-				}
-				continue
-			}
-			closeMatcher.Reset()
-			if closeMatchDone && openMatchDone {
-				tagLen++
-			}
-			mode = parseModeNone
-			count += tagLen
-			tagLen = 0
-			continue
+			i += size
 		}
 	}
-	if tagLen > 0 {
-		count += tagLen
-	}
 	return count
 }
@@ -109,6 +109,41 @@ func TestSplitStringAllSegmentsParseable(t *testing.T) {
 	}
 }
 
+func TestSplitStringMultiByte(t *testing.T) {
+	result := SplitString(`<ansi fg="red">café</ansi> naïve`, 5, false)
+	assert.Equal(t, []string{`<ansi fg="red">café</ansi> `, `naïve`}, result)
+}
+
+func TestSplitStringWideCharacters(t *testing.T) {
+	// A wide character that would straddle the limit moves to the next segment
+	result := SplitString("ab日本語", 3, false)
+	assert.Equal(t, []string{"ab", "日", "本", "語"}, result)
+
+	result = SplitString(`<ansi fg="red">日本語</ansi>`, 4, false)
+	assert.Equal(t, []string{`<ansi fg="red">日本</ansi>`, `<ansi fg="red">語</ansi>`}, result)
+}
+
+func TestSplitStringCombiningMarks(t *testing.T) {
+	// Combining marks stay with the character they modify
+	result := SplitString("e\u0301e\u0301e\u0301", 2, false)
+	assert.Equal(t, []string{"e\u0301e\u0301", "e\u0301"}, result)
+}
+
+func TestVisibleLenCRLF(t *testing.T) {
+	// Tabs after a \r\n line ending are measured from the start of the line
+	assert.Equal(t, 12, visibleLen("abc\r\n\tx"))
+}
+
+func TestSplitStringEscaped(t *testing.T) {
+	// An escaped < is one character and is never split from its backslash
+	result := SplitString(`a<\b<\c`, 2, false)
+	assert.Equal(t, []string{`a<\`, `b<\`, `c`}, result)
+
+	for _, seg := range result {
+		assert.NotContains(t, Parse(seg), `\`)
+	}
+}
+
 // --- SplitString trimSpace tests (default true) ---
 
 func TestSplitStringTrimDefault(t *testing.T) {
@@ -226,6 +261,14 @@ func TestSplitStringOnSpacesMixedFallback(t *testing.T) {
 	}
 }
 
+func TestSplitStringOnSpacesWide(t *testing.T) {
+	result := SplitStringOnSpaces("日本 語です", 6)
+	assert.Equal(t, []string{"日本", "語です"}, result)
+	for _, seg := range result {
+		assert.LessOrEqual(t, VisibleWidth(seg), 6)
+	}
+}
+
 // --- Benchmarks ---
 
 func BenchmarkSplitString(b *testing.B) {
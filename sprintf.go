@@ -0,0 +1,362 @@
+package ansitags
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Tagged marks a Sprintf argument as trusted tagged text. It is written as
+// is, so any tags in it are kept.
+type Tagged string
+
+// verbSpec is a parsed formatting verb, such as %-20s or %[2]*.3f.
+type verbSpec struct {
+	flags     string // every flag but '-', in the order written
+	minus     bool
+	zero      bool
+	width     int
+	hasWidth  bool
+	precision string // ".N" or ""
+	verb      rune
+}
+
+// Sprintf formats according to format like fmt.Sprintf, except that every
+// argument is escaped so that tags in it are printed as text rather than
+// parsed. Only a < that could start a tag is escaped, so text such as the
+// <nil> in fmt's %!s(<nil>) is written as fmt writes it. The format itself
+// may contain tags. Use Tagged for an argument that holds trusted tagged
+// text.
+//
+// Widths count visible columns, so %-20s pads a name to 20 columns however
+// many bytes it takes up. Slices, arrays, maps and structs are padded by fmt
+// instead, one element at a time as fmt.Sprintf does. An argument can't be
+// used inside a tag, since its value could add attributes: the tag is
+// escaped and printed as text instead.
+//
+// Usage:
+//
+//	msg := ansitags.Sprintf(`<ansi fg="cyan">%-12s</ansi> says "%s"`, playerName, said)
+//	fmt.Println(ansitags.Parse(msg))
+func Sprintf(format string, args ...any) string {
+
+	format = escapeVerbTags(format)
+
+	var out strings.Builder
+	out.Grow(len(format))
+
+	argNum := 0
+	reordered := false
+
+	for i := 0; i < len(format); {
+		next := strings.IndexByte(format[i:], '%')
+		if next < 0 {
+			out.WriteString(format[i:])
+			break
+		}
+		out.WriteString(format[i : i+next])
+		i += next + 1
+
+		var spec verbSpec
+	flags:
+		for ; i < len(format); i++ {
+			switch format[i] {
+			case '-':
+				spec.minus = true
+				continue
+			case '0':
+				spec.zero = true
+			case '+', '#', ' ':
+			default:
+				break flags
+			}
+			spec.flags += format[i : i+1]
+		}
+
+		var ok bool
+		argNum, i, ok = argIndex(format, i, argNum, len(args))
+		reordered = reordered || ok
+
+		if i < len(format) && format[i] == '*' {
+			i++
+			if argNum >= len(args) {
+				out.WriteString("%!(BADWIDTH)")
+			} else if n, isInt := args[argNum].(int); !isInt {
+				out.WriteString("%!(BADWIDTH)")
+				argNum++
+			} else {
+				spec.width, spec.hasWidth = n, true
+				if n < 0 {
+					spec.width, spec.minus = -n, true
+				}
+				argNum++
+			}
+		} else {
+			start := i
+			for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+				i++
+			}
+			if i > start {
+				spec.width, _ = strconv.Atoi(format[start:i])
+				spec.hasWidth = true
+			}
+		}
+
+		if i < len(format) && format[i] == '.' {
+			i++
+			argNum, i, ok = argIndex(format, i, argNum, len(args))
+			reordered = reordered || ok
+
+			if i < len(format) && format[i] == '*' {
+				i++
+				if n, isInt := argAt(args, argNum).(int); isInt && n >= 0 {
+					spec.precision = "." + strconv.Itoa(n)
+				} else {
+					out.WriteString("%!(BADPREC)")
+				}
+				argNum++
+			} else {
+				start := i
+				for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+					i++
+				}
+				spec.precision = "." + format[start:i]
+			}
+		}
+
+		argNum, i, ok = argIndex(format, i, argNum, len(args))
+		reordered = reordered || ok
+
+		if i >= len(format) {
+			out.WriteString("%!(NOVERB)")
+			break
+		}
+
+		var size int
+		spec.verb, size = utf8.DecodeRuneInString(format[i:])
+		i += size
+
+		switch {
+		case spec.verb == '%':
+			out.WriteByte('%')
+		case argNum >= len(args):
+			out.WriteString("%!" + string(spec.verb) + "(MISSING)")
+		default:
+			out.WriteString(spec.format(args[argNum]))
+			argNum++
+		}
+	}
+
+	if !reordered && argNum < len(args) {
+		extra := make([]string, 0, len(args)-argNum)
+		for _, arg := range args[argNum:] {
+			if arg == nil {
+				extra = append(extra, "<nil>")
+			} else {
+				extra = append(extra, fmt.Sprintf("%T=%v", arg, arg))
+			}
+		}
+		out.WriteString(escapeArg("%!(EXTRA " + strings.Join(extra, ", ") + ")"))
+	}
+
+	return out.String()
+}
+
+// format writes arg with fmt, then escapes it and pads it to the width by
+// visible columns.
+func (v verbSpec) format(arg any) string {
+
+	// fmt pads each element of a composite value, so it is left to do so
+	elements := padsElements(arg, v.verb)
+
+	spec := "%" + v.flags
+	if elements && v.minus {
+		spec += "-"
+	}
+	if v.hasWidth && (elements || (v.zero && !v.minus)) {
+		// Zero padding is left to fmt too, which knows where the sign goes
+		spec += strconv.Itoa(v.width)
+	}
+	spec += v.precision + string(v.verb)
+
+	text := fmt.Sprintf(spec, arg)
+
+	var width int
+	if _, ok := arg.(Tagged); ok && (v.verb == 's' || v.verb == 'v') {
+		width = VisibleWidth(text)
+	} else {
+		width = plainWidth(text, 0)
+		text = escapeArg(text)
+	}
+
+	if !v.hasWidth || elements || width >= v.width {
+		return text
+	}
+	if v.minus {
+		return text + padding(v.width-width, ' ')
+	}
+	return padding(v.width-width, ' ') + text
+}
+
+// padsElements reports whether fmt pads each element of arg rather than the
+// value as a whole, as it does for slices, arrays, maps and structs, and
+// pointers to them, that don't format themselves.
+func padsElements(arg any, verb rune) bool {
+
+	switch arg.(type) {
+	case fmt.Formatter, fmt.Stringer, error:
+		return false
+	}
+	if verb == 'T' || verb == 'p' {
+		return false
+	}
+
+	t := reflect.TypeOf(arg)
+	if t == nil {
+		return false
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		// Bytes are written as one string by the string verbs
+		return t.Elem().Kind() != reflect.Uint8 || !strings.ContainsRune("sqxX", verb)
+	case reflect.Map, reflect.Struct:
+		return true
+	}
+
+	return false
+}
+
+// escapeArg escapes every tagStart in text that could start a tag or an
+// escape, either within text or together with what is written after it.
+// Any other tagStart is left as fmt wrote it, such as the one in <nil>.
+func escapeArg(text string) string {
+
+	var out strings.Builder
+
+	last := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] != tagStart || !mayStartTag(text[i+1:]) {
+			continue
+		}
+		out.WriteString(text[last : i+1])
+		out.WriteByte(tagEscape)
+		last = i + 1
+	}
+
+	if last == 0 {
+		return text
+	}
+
+	out.WriteString(text[last:])
+	return out.String()
+}
+
+// mayStartTag reports whether rest, the text right after a tagStart, could
+// carry on into a tag or an escape.
+func mayStartTag(rest string) bool {
+
+	if len(rest) > 0 && rest[0] == tagEscape {
+		return true
+	}
+
+	for _, name := range []string{tagOpen, tagClose} {
+		n := len(rest)
+		if n > len(name) {
+			n = len(name)
+		}
+		if rest[:n] == name[:n] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// argIndex reads an explicit argument index such as [2] at format[i], and
+// returns the argument number to use next, the position after the index,
+// and whether an index was found.
+func argIndex(format string, i int, argNum int, numArgs int) (int, int, bool) {
+
+	if i >= len(format) || format[i] != '[' {
+		return argNum, i, false
+	}
+
+	end := strings.IndexByte(format[i:], ']')
+	if end < 0 {
+		return argNum, i, false
+	}
+
+	n, err := strconv.Atoi(format[i+1 : i+end])
+	if err != nil || n < 1 || n > numArgs {
+		// Out of range, so the verb that follows reports the argument missing
+		return numArgs, i + end + 1, true
+	}
+
+	return n - 1, i + end + 1, true
+}
+
+func argAt(args []any, n int) any {
+	if n < len(args) {
+		return args[n]
+	}
+	return nil
+}
+
+// escapeVerbTags escapes every tag in format that has a formatting verb
+// inside it, along with a tagStart left open before a verb, so that no
+// argument can become part of a tag.
+func escapeVerbTags(format string) string {
+
+	var out strings.Builder
+
+	last := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != tagStart {
+			continue
+		}
+		if i+1 < len(format) && format[i+1] == tagEscape {
+			i++
+			continue
+		}
+
+		tag := format[i:]
+		if end := strings.IndexByte(tag, tagEnd); end >= 0 {
+			tag = tag[:end]
+		}
+		if !hasVerb(tag) {
+			continue
+		}
+
+		out.WriteString(format[last : i+1])
+		out.WriteByte(tagEscape)
+		last = i + 1
+	}
+
+	if last == 0 {
+		return format
+	}
+
+	out.WriteString(format[last:])
+	return out.String()
+}
+
+// hasVerb reports whether str holds a formatting verb other than %%.
+func hasVerb(str string) bool {
+	for i := 0; i < len(str); i++ {
+		if str[i] != '%' {
+			continue
+		}
+		if i+1 < len(str) && str[i+1] == '%' {
+			i++
+			continue
+		}
+		return true
+	}
+	return false
+}
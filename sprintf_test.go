@@ -0,0 +1,108 @@
+package ansitags
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSprintfEscapesArguments(t *testing.T) {
+	said := `<ansi clear=all>hi</ansi>`
+	result := Sprintf(`<ansi fg="cyan">%s</ansi> says "%s"`, "Bob", said)
+
+	assert.Equal(t, `<ansi fg="cyan">Bob</ansi> says "<\ansi clear=all>hi<\/ansi>"`, result)
+	assert.Equal(t, "\x1b[38;5;6m\x1b[49mBob\x1b[0m says \"<ansi clear=all>hi</ansi>\"", Parse(result))
+	assert.Equal(t, `Bob says "<ansi clear=all>hi</ansi>"`, Parse(result, StripTags))
+}
+
+func TestSprintfTagged(t *testing.T) {
+	name := Tagged(`<ansi fg="red">Bob</ansi>`)
+	result := Sprintf("%-5s|%5v|%s", name, name, "<")
+	assert.Equal(t, `<ansi fg="red">Bob</ansi>  |  <ansi fg="red">Bob</ansi>|<\`, result)
+}
+
+func TestSprintfVisibleWidth(t *testing.T) {
+	assert.Equal(t, "[日本    ]", Sprintf("[%-8s]", "日本"))
+	assert.Equal(t, "[    日本]", Sprintf("[%8s]", "日本"))
+	assert.Equal(t, "[<b>  ]", Sprintf("[%-5s]", "<b>"))
+	assert.Equal(t, "[<\\ansi>  ]", Sprintf("[%-8s]", "<ansi>"))
+	assert.Equal(t, "[ <\\\\x]", Sprintf("[%4s]", `<\x`))
+	assert.Equal(t, "[ab]", Sprintf("[%1s]", "ab"))
+	assert.Equal(t, "[  ab]", Sprintf("[%*s]", 4, "ab"))
+	assert.Equal(t, "[ab  ]", Sprintf("[%*s]", -4, "ab"))
+}
+
+func TestSprintfMatchesFmt(t *testing.T) {
+	cases := []struct {
+		format string
+		args   []any
+	}{
+		{"%d items at %.2f each, 100%%", []any{3, 1.5}},
+		{"%05d|%-5d|%+d|%x|%#x|%q", []any{42, 42, 42, 255, 255, "hi"}},
+		{"%[2]s %[1]s", []any{"world", "hello"}},
+		{"%.*f", []any{2, 3.14159}},
+		{"%s and %s", []any{"one"}},
+		{"%s", []any{"one", 2, nil}},
+		{"%v %T", []any{true, 1.0}},
+		{"100%", nil},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, fmt.Sprintf(c.format, c.args...), Parse(Sprintf(c.format, c.args...)), c.format)
+	}
+}
+
+func TestSprintfFmtErrors(t *testing.T) {
+	// fmt's own text is written as is
+	assert.Equal(t, "%!s(<nil>)", Sprintf("%s", nil))
+	assert.Equal(t, "<nil>", Sprintf("%v", nil))
+	assert.Equal(t, "a%!(EXTRA <nil>)", Sprintf("a", nil))
+
+	// But not an argument inside it
+	assert.Equal(t, `%!d(string=<\ansi>)`, Sprintf("%d", "<ansi>"))
+}
+
+func TestSprintfComposite(t *testing.T) {
+	cases := []struct {
+		format string
+		arg    any
+	}{
+		{"%10v|", []string{"a", "b"}},
+		{"%-4v|", []int{1, 22}},
+		{"%05v|", [2]int{1, 2}},
+		{"%6v|", map[string]int{"a": 1}},
+		{"%-4v|", struct {
+			A int
+			B string
+		}{1, "b"}},
+		{"%6v|", &[]int{1}},
+		{"%6s|", []byte("ab")},
+		{"%6v|", []byte("ab")},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, fmt.Sprintf(c.format, c.arg), Sprintf(c.format, c.arg), c.format)
+	}
+
+	// Elements are still escaped
+	assert.Equal(t, `[<\ansi>      x]`, Sprintf("%6v", []string{"<ansi>", "x"}))
+}
+
+func TestSprintfArgumentInsideTag(t *testing.T) {
+	// An argument could add attributes to the tag, so the tag is printed as text
+	result := Sprintf(`<ansi fg="%s">hi</ansi>`, `red" clear="all`)
+	assert.Equal(t, `<ansi fg="red" clear="all">hi`, Parse(result, StripTags))
+
+	result = Sprintf(`<%s> says hi`, "ansi clear=all")
+	assert.Equal(t, `<ansi clear=all> says hi`, Parse(result))
+
+	// %% isn't a verb, so the tag stays
+	assert.Equal(t, "<ansi fg=red>100%</ansi>", Sprintf(`<ansi fg=red>%d%%</ansi>`, 100))
+}
+
+func TestSprintfArgumentEndingInBackslash(t *testing.T) {
+	result := Sprintf(`<ansi fg=red>%s</ansi>`, `C:\`)
+	assert.Equal(t, `<ansi fg=red>C:\</ansi>`, result)
+	assert.Equal(t, `C:\`, Parse(result, StripTags))
+}
@@ -0,0 +1,171 @@
+package ansitags
+
+import (
+	"html"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Palette maps each of the 256 color codes to the color drawn for it.
+type Palette [256]color.RGBA
+
+// DefaultPalette returns the palette used by RGB().
+func DefaultPalette() *Palette {
+	var p Palette
+	for i, clr := range ansi256 {
+		p[i] = color.RGBA{R: clr.R, G: clr.G, B: clr.B, A: 0xff}
+	}
+	return &p
+}
+
+// SVGOptions controls how RenderSVG lays out text. Zero values fall back to
+// the defaults noted on each field.
+type SVGOptions struct {
+	FontFamily string      // default "monospace"
+	FontSize   float64     // default 14
+	CellWidth  float64     // width of one column, default 0.6 * FontSize
+	CellHeight float64     // height of one line, default 1.25 * FontSize
+	Palette    *Palette    // default DefaultPalette()
+	Foreground color.Color // color of untagged text, default palette color 7
+	Background color.Color // image background, default palette color 0
+}
+
+func (o SVGOptions) withDefaults() SVGOptions {
+	if o.FontFamily == "" {
+		o.FontFamily = "monospace"
+	}
+	if o.FontSize <= 0 {
+		o.FontSize = 14
+	}
+	if o.CellWidth <= 0 {
+		o.CellWidth = o.FontSize * 0.6
+	}
+	if o.CellHeight <= 0 {
+		o.CellHeight = o.FontSize * 1.25
+	}
+	if o.Palette == nil {
+		o.Palette = DefaultPalette()
+	}
+	if o.Foreground == nil {
+		o.Foreground = o.Palette[7]
+	}
+	if o.Background == nil {
+		o.Background = o.Palette[0]
+	}
+	return o
+}
+
+// RenderSVG draws a tagged string as an SVG image on a fixed character grid.
+// Each line of input becomes a <text> element, colored runs become <tspan>
+// elements and background colors are drawn as <rect> elements behind them.
+//
+// Usage:
+//
+// svg := ansitags.RenderSVG(`<ansi fg="red">Hello</ansi> world`, ansitags.SVGOptions{FontSize: 16})
+// os.WriteFile("hello.svg", []byte(svg), 0644)
+func RenderSVG(str string, opts SVGOptions) string {
+
+	opts = opts.withDefaults()
+
+	lines := runLines(Runs(str))
+
+	columns := 0
+	for _, line := range lines {
+		lineLen := 0
+		for _, run := range line {
+			lineLen += plainWidth(run.Text, lineLen)
+		}
+		if lineLen > columns {
+			columns = lineLen
+		}
+	}
+
+	width := float64(columns) * opts.CellWidth
+	height := float64(len(lines)) * opts.CellHeight
+
+	var rects strings.Builder
+	var texts strings.Builder
+
+	for row, line := range lines {
+
+		y := float64(row) * opts.CellHeight
+		baseline := y + (opts.CellHeight+opts.FontSize*0.7)/2
+
+		texts.WriteString(`<text y="` + formatFloat(baseline) + `">`)
+
+		col := 0
+		for _, run := range line {
+			runLen := plainWidth(run.Text, col)
+			x := float64(col) * opts.CellWidth
+
+			if run.Bg != nil {
+				rects.WriteString(`<rect x="` + formatFloat(x) + `" y="` + formatFloat(y) +
+					`" width="` + formatFloat(float64(runLen)*opts.CellWidth) + `" height="` + formatFloat(opts.CellHeight) +
+					`" fill="` + svgColor(opts.Palette[run.Bg.Index]) + `"/>`)
+			}
+
+			fill := opts.Foreground
+			if run.Fg != nil {
+				fill = opts.Palette[run.Fg.Index]
+			}
+
+			texts.WriteString(`<tspan x="` + formatFloat(x) + `" fill="` + svgColor(fill) + `">` + svgText(run.Text) + `</tspan>`)
+			col += runLen
+		}
+
+		texts.WriteString("</text>")
+	}
+
+	var out strings.Builder
+	out.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="` + formatFloat(width) + `" height="` + formatFloat(height) +
+		`" viewBox="0 0 ` + formatFloat(width) + ` ` + formatFloat(height) + `">`)
+	out.WriteString(`<rect width="100%" height="100%" fill="` + svgColor(opts.Background) + `"/>`)
+	out.WriteString(rects.String())
+	out.WriteString(`<g font-family="` + svgText(opts.FontFamily) + `" font-size="` + formatFloat(opts.FontSize) + `" xml:space="preserve">`)
+	out.WriteString(texts.String())
+	out.WriteString("</g></svg>")
+
+	return out.String()
+}
+
+// runLines splits runs on newlines, returning the runs that make up each line.
+func runLines(runs []Run) [][]Run {
+	lines := [][]Run{nil}
+	for _, run := range runs {
+		parts := strings.Split(run.Text, "\n")
+		for i, part := range parts {
+			if i > 0 {
+				lines = append(lines, nil)
+			}
+			if len(part) > 0 {
+				lines[len(lines)-1] = append(lines[len(lines)-1], Run{Text: part, Fg: run.Fg, Bg: run.Bg})
+			}
+		}
+	}
+	return lines
+}
+
+// svgText escapes s for use in SVG text or attributes. C0 control characters
+// other than tab, newline and carriage return aren't allowed anywhere in XML 1.0,
+// so they're dropped.
+func svgText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' {
+			return -1
+		}
+		return r
+	}, s)
+	return html.EscapeString(s)
+}
+
+func svgColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return "#" + newRGB(uint8(r>>8), uint8(g>>8), uint8(b>>8)).Hex
+}
+
+// formatFloat writes f with at most two decimal places.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,61 @@
+package ansitags
+
+import (
+	"encoding/xml"
+	"image/color"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderSVGDimensions(t *testing.T) {
+	svg := RenderSVG("abc\nde", SVGOptions{FontSize: 10, CellWidth: 6, CellHeight: 12})
+
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="18" height="24" viewBox="0 0 18 24">`))
+	assert.True(t, strings.HasSuffix(svg, "</g></svg>"))
+	assert.Equal(t, 2, strings.Count(svg, "<text "))
+}
+
+func TestRenderSVGColors(t *testing.T) {
+	svg := RenderSVG(`a<ansi fg="red" bg="blue">b</ansi>`, SVGOptions{FontSize: 10, CellWidth: 6, CellHeight: 12})
+
+	assert.Contains(t, svg, `<tspan x="0" fill="#c0c0c0">a</tspan>`)
+	assert.Contains(t, svg, `<tspan x="6" fill="#800000">b</tspan>`)
+	assert.Contains(t, svg, `<rect x="6" y="0" width="6" height="12" fill="#000080"/>`)
+}
+
+func TestRenderSVGCustomPalette(t *testing.T) {
+	palette := DefaultPalette()
+	palette[1] = color.RGBA{R: 0x12, G: 0x34, B: 0x56, A: 0xff}
+
+	svg := RenderSVG(`<ansi fg="red">x</ansi>`, SVGOptions{Palette: palette, Background: color.White, FontFamily: "Fira Mono"})
+
+	assert.Contains(t, svg, `fill="#123456">x</tspan>`)
+	assert.Contains(t, svg, `<rect width="100%" height="100%" fill="#ffffff"/>`)
+	assert.Contains(t, svg, `font-family="Fira Mono"`)
+}
+
+func TestRenderSVGEscapesText(t *testing.T) {
+	svg := RenderSVG(`1 < 2 & "x"`, SVGOptions{})
+
+	assert.Contains(t, svg, `>1 &lt; 2 &amp; &#34;x&#34;</tspan>`)
+}
+
+func TestRenderSVGDropsControls(t *testing.T) {
+	svg := RenderSVG("bell\x07 <ansi fg=\"red\">back\bspace</ansi>", SVGOptions{FontFamily: "mono\x00"})
+
+	assert.Contains(t, svg, `>bell </tspan>`)
+	assert.Contains(t, svg, `>backspace</tspan>`)
+	assert.Contains(t, svg, `font-family="mono"`)
+
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if err != nil {
+			assert.Equal(t, io.EOF, err)
+			break
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package ansitags
+
+import "strings"
+
+// Alignment positions text within a column.
+type Alignment uint8
+
+const (
+	AlignLeft Alignment = iota
+	AlignRight
+	AlignCenter
+)
+
+// Column holds the layout settings for one table column.
+type Column struct {
+	Align    Alignment
+	MaxWidth int  // widest the column may be, 0 for no limit
+	Truncate bool // cut cells wider than MaxWidth with "…" instead of wrapping them
+}
+
+// Table lays out rows of tagged cells in aligned columns. Column widths are
+// measured by visible width, so tags and wide characters line up.
+//
+// Usage:
+//
+//	t := ansitags.NewTable("Name", "Level", "Class")
+//	t.AddRow(`<ansi fg="cyan">Volothamp</ansi>`, "12", "Wizard")
+//	t.AddRow("Bob", "3", `<ansi fg="red">Fighter</ansi>`)
+//	t.SetColumn(1, ansitags.Column{Align: ansitags.AlignRight})
+//	t.Border = &ansitags.BorderRounded
+//	fmt.Print(ansitags.Parse(t.Render()))
+type Table struct {
+	Header      []string
+	Rows        [][]string
+	Columns     []Column
+	Border      *BorderSet // nil draws no border, columns are separated by spaces
+	BorderStyle string     // tag attributes or an alias for the border
+	HeaderStyle string     // tag attributes or an alias for the header row
+	AltRowStyle string     // tag attributes or an alias for every second row
+	Padding     int        // spaces on either side of each cell
+}
+
+// NewTable returns a table with the given header cells and a padding of 1.
+// Leave the header empty for a table without one.
+func NewTable(header ...string) *Table {
+	return &Table{
+		Header:  header,
+		Padding: 1,
+	}
+}
+
+// AddRow adds a row of cells to the table.
+func (t *Table) AddRow(cells ...string) *Table {
+	t.Rows = append(t.Rows, cells)
+	return t
+}
+
+// SetColumn sets the layout of the column at index.
+func (t *Table) SetColumn(index int, col Column) *Table {
+	for len(t.Columns) <= index {
+		t.Columns = append(t.Columns, Column{})
+	}
+	t.Columns[index] = col
+	return t
+}
+
+// Render returns the table as tagged text, one line per row of text, each
+// ending in a newline.
+func (t *Table) Render() string {
+
+	count := len(t.Header)
+	for _, row := range t.Rows {
+		if len(row) > count {
+			count = len(row)
+		}
+	}
+	if count == 0 {
+		return ""
+	}
+
+	columns := make([]Column, count)
+	copy(columns, t.Columns)
+
+	widths := make([]int, count)
+	measure := func(row []string) {
+		for i, cell := range row {
+			if w := VisibleWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	measure(t.Header)
+	for _, row := range t.Rows {
+		measure(row)
+	}
+	for i, col := range columns {
+		if col.MaxWidth > 0 && widths[i] > col.MaxWidth {
+			widths[i] = col.MaxWidth
+		}
+	}
+
+	pad := ""
+	if t.Padding > 0 {
+		pad = strings.Repeat(" ", t.Padding)
+	}
+
+	var out strings.Builder
+
+	rule := func(left rune, mid rune, right rune) {
+		if t.Border == nil {
+			return
+		}
+		var line strings.Builder
+		line.WriteRune(left)
+		for i, w := range widths {
+			if i > 0 {
+				line.WriteRune(mid)
+			}
+			line.WriteString(strings.Repeat(string(t.Border.Horizontal), w+len(pad)*2))
+		}
+		line.WriteRune(right)
+		out.WriteString(styled(t.BorderStyle, line.String()) + "\n")
+	}
+
+	writeRow := func(row []string, style string) {
+		cells := make([][]string, count)
+		height := 1
+		for i := range cells {
+			cell := ""
+			if i < len(row) {
+				cell = row[i]
+			}
+			cells[i] = t.cellLines(cell, widths[i], columns[i])
+			if len(cells[i]) > height {
+				height = len(cells[i])
+			}
+		}
+
+		vertical := ""
+		if t.Border != nil {
+			vertical = styled(t.BorderStyle, string(t.Border.Vertical))
+		}
+
+		for l := 0; l < height; l++ {
+			out.WriteString(vertical)
+			for i := range cells {
+				text := ""
+				if l < len(cells[i]) {
+					text = cells[i][l]
+				}
+				text = align(text, widths[i], columns[i].Align)
+
+				switch {
+				case t.Border != nil:
+					out.WriteString(styled(style, pad+text+pad) + vertical)
+				case i == count-1:
+					out.WriteString(styled(style, text))
+				default:
+					out.WriteString(styled(style, text+pad+pad))
+				}
+			}
+			out.WriteString("\n")
+		}
+	}
+
+	if t.Border != nil {
+		rule(t.Border.TopLeft, t.Border.TopT, t.Border.TopRight)
+	}
+
+	if len(t.Header) > 0 {
+		writeRow(t.Header, t.HeaderStyle)
+		if t.Border != nil {
+			rule(t.Border.LeftT, t.Border.Cross, t.Border.RightT)
+		}
+	}
+
+	for i, row := range t.Rows {
+		style := ""
+		if i%2 == 1 {
+			style = t.AltRowStyle
+		}
+		writeRow(row, style)
+	}
+
+	if t.Border != nil {
+		rule(t.Border.BottomLeft, t.Border.BottomT, t.Border.BottomRight)
+	}
+
+	return out.String()
+}
+
+// cellLines fits a cell to the column width, wrapping or truncating it.
+func (t *Table) cellLines(cell string, width int, col Column) []string {
+
+	if col.Truncate {
+		return []string{Truncate(cell, width, "…")}
+	}
+
+	if VisibleWidth(cell) <= width && !strings.Contains(cell, "\n") {
+		return []string{cell}
+	}
+
+	return Wrap(cell, width, WrapOptions{})
+}
+
+// align pads text to width according to the alignment.
+func align(text string, width int, alignment Alignment) string {
+	switch alignment {
+	case AlignRight:
+		return PadLeft(text, width, ' ')
+	case AlignCenter:
+		return Center(text, width, ' ')
+	}
+	return PadRight(text, width, ' ')
+}
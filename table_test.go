@@ -0,0 +1,101 @@
+package ansitags
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableNoBorder(t *testing.T) {
+	table := NewTable("Name", "Lvl")
+	table.AddRow(`<ansi fg="cyan">Volothamp</ansi>`, "12")
+	table.AddRow("Bob", "3")
+	table.SetColumn(1, Column{Align: AlignRight})
+
+	expected := "" +
+		"Name       Lvl\n" +
+		`<ansi fg="cyan">Volothamp</ansi>   12` + "\n" +
+		"Bob          3\n"
+
+	assert.Equal(t, expected, table.Render())
+}
+
+func TestTableBorder(t *testing.T) {
+	table := NewTable("Item", "Cost")
+	table.AddRow("Sword", "15")
+	table.AddRow("Shield", "8")
+	table.SetColumn(1, Column{Align: AlignCenter})
+	table.Border = &BorderASCII
+
+	expected := "" +
+		"+--------+------+\n" +
+		"| Item   | Cost |\n" +
+		"+--------+------+\n" +
+		"| Sword  |  15  |\n" +
+		"| Shield |  8   |\n" +
+		"+--------+------+\n"
+
+	assert.Equal(t, expected, table.Render())
+}
+
+func TestTableMaxWidth(t *testing.T) {
+	table := NewTable()
+	table.AddRow("Long sword of fire", "x")
+	table.SetColumn(0, Column{MaxWidth: 10})
+	table.Border = &BorderSingle
+
+	expected := "" +
+		"┌────────────┬───┐\n" +
+		"│ Long sword │ x │\n" +
+		"│ of fire    │   │\n" +
+		"└────────────┴───┘\n"
+
+	assert.Equal(t, expected, table.Render())
+
+	table.SetColumn(0, Column{MaxWidth: 10, Truncate: true})
+	assert.Equal(t, "│ Long swor… │ x │", strings.Split(table.Render(), "\n")[1])
+}
+
+func TestTableStyles(t *testing.T) {
+	table := NewTable("A")
+	table.AddRow("one")
+	table.AddRow("two")
+	table.Border = &BorderSingle
+	table.BorderStyle = "blue"
+	table.HeaderStyle = `fg="yellow"`
+	table.AltRowStyle = `bg="black"`
+
+	lines := strings.Split(table.Render(), "\n")
+
+	assert.Equal(t, `<ansi fg="blue">┌─────┐</ansi>`, lines[0])
+	assert.Equal(t, `<ansi fg="blue">│</ansi><ansi fg="yellow"> A   </ansi><ansi fg="blue">│</ansi>`, lines[1])
+	assert.Equal(t, `<ansi fg="blue">│</ansi> one <ansi fg="blue">│</ansi>`, lines[3])
+	assert.Equal(t, `<ansi fg="blue">│</ansi><ansi bg="black"> two </ansi><ansi fg="blue">│</ansi>`, lines[4])
+}
+
+func TestTableRaggedRows(t *testing.T) {
+	table := NewTable("A", "B", "C")
+	table.AddRow("1")
+	table.AddRow("1", "2", "3", "4")
+	table.Padding = 0
+	table.Border = &BorderASCII
+
+	for _, line := range strings.Split(strings.TrimSuffix(table.Render(), "\n"), "\n") {
+		assert.Equal(t, 9, VisibleWidth(line), "line=%q", line)
+	}
+}
+
+func TestTableWide(t *testing.T) {
+	table := NewTable("名前", "HP")
+	table.AddRow("Bob", "10")
+	table.Border = &BorderASCII
+
+	for _, line := range strings.Split(strings.TrimSuffix(table.Render(), "\n"), "\n") {
+		assert.Equal(t, 13, VisibleWidth(line), "line=%q", line)
+	}
+}
+
+func TestTableEmpty(t *testing.T) {
+	assert.Equal(t, "", NewTable().Render())
+}
@@ -0,0 +1,91 @@
+package ansitags
+
+type tokenKind uint8
+
+const (
+	tokenText  tokenKind = iota // visible text between tags
+	tokenOpen                   // an opening tag such as <ansi fg=red>
+	tokenClose                  // a closing tag, </ansi>
+)
+
+// token is a raw slice of tagged input classified the same way Parse sees it.
+type token struct {
+	kind tokenKind
+	raw  string
+}
+
+// tokenize splits input into text, open tag and close tag tokens. It walks the
+// input with the same tag matchers as parseString so that anything Parse
+// treats as a tag is a tag here, and anything it writes through is text.
+// Adjacent text is merged into a single token.
+func tokenize(input string) []token {
+
+	tokens := make([]token, 0, 8)
+
+	openMatcher := NewTagMatcher(tagStart, []byte(tagOpen), tagEnd, true)
+	closeMatcher := NewTagMatcher(tagStart, []byte(tagClose), tagEnd, false)
+
+	var mode parseMode = parseModeNone
+
+	textStart := 0
+	tagStartIdx := 0
+
+	emitTag := func(kind tokenKind, start int, end int) {
+		if textStart < start {
+			tokens = append(tokens, token{kind: tokenText, raw: input[textStart:start]})
+		}
+		tokens = append(tokens, token{kind: kind, raw: input[start:end]})
+		textStart = end
+	}
+
+	for i := 0; i < len(input); i++ {
+		ch := input[i]
+
+		if mode == parseModeNone {
+			if ch != tagStart {
+				continue
+			}
+			mode = parseModeMatching
+			tagStartIdx = i
+		}
+
+		if mode == parseModeMatching {
+			openMatch, openMatchDone := openMatcher.MatchNext(ch)
+			closeMatch, closeMatchDone := closeMatcher.MatchNext(ch)
+
+			if openMatch {
+				if !openMatchDone {
+					continue
+				}
+				emitTag(tokenOpen, tagStartIdx, i+1)
+				mode = parseModeNone
+				openMatcher.Reset()
+				closeMatcher.Reset()
+				continue
+			}
+			openMatcher.Reset()
+
+			if closeMatch {
+				if !closeMatchDone {
+					continue
+				}
+				emitTag(tokenClose, tagStartIdx, i+1)
+				mode = parseModeNone
+				openMatcher.Reset()
+				closeMatcher.Reset()
+				continue
+			}
+			closeMatcher.Reset()
+
+			// Not a tag after all, the bytes stay part of the text.
+			mode = parseModeNone
+			continue
+		}
+	}
+
+	if textStart < len(input) {
+		tokens = append(tokens, token{kind: tokenText, raw: input[textStart:]})
+	}
+
+	return tokens
+}
@@ -0,0 +1,49 @@
+package ansitags
+
+// Truncate cuts str down to at most width visible columns, ending it with
+// tail if anything was cut. Tags are never broken: any that are open at the
+// cut are closed before tail is added, so tail can carry its own tags. If
+// tail is wider than width, str is cut without it.
+//
+// Usage:
+//
+// ansitags.Truncate(`<ansi fg="yellow">The Grand Hall of the Mountain King</ansi>`, 16, "…")
+// ansitags.Truncate(name, 12, `<ansi fg="black-bold">...</ansi>`)
+func Truncate(str string, width int, tail string) string {
+
+	if visibleLen(str) <= width {
+		return str
+	}
+
+	keep := width - VisibleWidth(tail)
+	if keep < 0 {
+		return Slice(str, 0, width)
+	}
+
+	return Slice(str, 0, keep) + tail
+}
+
+// TruncateMiddle cuts str down to at most width visible columns by removing
+// text from its middle and putting tail in its place, keeping the start and
+// end of str. Any extra column is given to the start.
+//
+// Usage:
+//
+// ansitags.TruncateMiddle("/home/mud/world/zones/frostfang/rooms/1.yaml", 24, "…")
+func TruncateMiddle(str string, width int, tail string) string {
+
+	total := visibleLen(str)
+	if total <= width {
+		return str
+	}
+
+	keep := width - VisibleWidth(tail)
+	if keep < 0 {
+		return Slice(str, 0, width)
+	}
+
+	right := keep / 2
+	left := keep - right
+
+	return Slice(str, 0, left) + tail + Slice(str, total-right, total)
+}
@@ -0,0 +1,45 @@
+package ansitags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "Short", Truncate("Short", 10, "…"))
+	assert.Equal(t, "Exactly 10", Truncate("Exactly 10", 10, "…"))
+	assert.Equal(t, "The Grand…", Truncate("The Grand Hall", 10, "…"))
+	assert.Equal(t, "The Gra...", Truncate("The Grand Hall", 10, "..."))
+	assert.Equal(t, "The Grand ", Truncate("The Grand Hall", 10, ""))
+}
+
+func TestTruncateClosesTags(t *testing.T) {
+	input := `<ansi fg="yellow">The <ansi fg="red">Grand</ansi> Hall</ansi>`
+
+	assert.Equal(t, `<ansi fg="yellow">The <ansi fg="red">Gr</ansi></ansi>…`, Truncate(input, 7, "…"))
+	assert.Equal(t, `<ansi fg="yellow">The <ansi fg="red">Grand</ansi></ansi>…`, Truncate(input, 10, "…"))
+	assert.Equal(t, `<ansi fg="yellow">The <ansi fg="red">Grand</ansi> </ansi><ansi fg="black">...</ansi>`,
+		Truncate(input, 13, `<ansi fg="black">...</ansi>`))
+}
+
+func TestTruncateWide(t *testing.T) {
+	// A wide character that doesn't fit before the tail is dropped
+	assert.Equal(t, "日本…", Truncate("日本語テキスト", 6, "…"))
+	assert.Equal(t, "日本…", Truncate("日本語テキスト", 5, "…"))
+	assert.LessOrEqual(t, VisibleWidth(Truncate("日本語テキスト", 5, "…")), 5)
+}
+
+func TestTruncateTailTooWide(t *testing.T) {
+	assert.Equal(t, "He", Truncate("Hello", 2, "..."))
+	assert.Equal(t, "", Truncate("Hello", 0, "…"))
+}
+
+func TestTruncateMiddle(t *testing.T) {
+	assert.Equal(t, "Short", TruncateMiddle("Short", 10, "…"))
+	assert.Equal(t, "/home/….yaml", TruncateMiddle("/home/mud/world/1.yaml", 12, "…"))
+	assert.Equal(t, "abcd...xyz", TruncateMiddle("abcdefghijklmnopqrstuvwxyz", 10, "..."))
+
+	input := `<ansi fg="red">abcdef</ansi><ansi fg="blue">uvwxyz</ansi>`
+	assert.Equal(t, `<ansi fg="red">abc</ansi>…<ansi fg="blue">xyz</ansi>`, TruncateMiddle(input, 7, "…"))
+}
@@ -0,0 +1,123 @@
+package ansitags
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValidationError describes a problem with the tags in a string.
+type ValidationError struct {
+	Offset  int    // byte offset of the offending tag in the input
+	Tag     string // the tag, or partial tag, the problem was found in
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("offset %d: %s: %s", e.Offset, e.Message, e.Tag)
+}
+
+// Validate checks a tagged string for mistakes that Parse silently tolerates:
+// unknown attributes, values that aren't valid colors/positions/clear modes,
+// close tags with nothing to close, tags left open and incomplete tags.
+// It returns nil if no problems are found.
+func Validate(str string) []ValidationError {
+
+	rwLock.RLock()
+	defer rwLock.RUnlock()
+
+	aliases := loadAliasSnapshot()
+
+	var problems []ValidationError
+	var openOffsets []int
+	var openTags []string
+
+	offset := 0
+	for _, tok := range tokenize(str) {
+
+		switch tok.kind {
+		case tokenOpen:
+			openOffsets = append(openOffsets, offset)
+			openTags = append(openTags, tok.raw)
+
+			for i := 0; i < len(tok.raw); {
+				key, val, next, ok := nextAttribute(tok.raw, i)
+				if !ok {
+					break
+				}
+				i = next
+
+				if msg := validateAttribute(key, val, aliases); msg != "" {
+					problems = append(problems, ValidationError{Offset: offset, Tag: tok.raw, Message: msg})
+				}
+			}
+
+		case tokenClose:
+			if len(openTags) == 0 {
+				problems = append(problems, ValidationError{Offset: offset, Tag: tok.raw, Message: "close tag without a matching open tag"})
+			} else {
+				openOffsets = openOffsets[:len(openOffsets)-1]
+				openTags = openTags[:len(openTags)-1]
+			}
+
+		default:
+			for _, partial := range []string{string(tagStart) + tagOpen, string(tagStart) + tagClose} {
+				if idx := strings.Index(tok.raw, partial); idx > -1 {
+					problems = append(problems, ValidationError{Offset: offset + idx, Tag: tok.raw[idx:], Message: "incomplete tag"})
+					break
+				}
+			}
+		}
+
+		offset += len(tok.raw)
+	}
+
+	for i := range openTags {
+		problems = append(problems, ValidationError{Offset: openOffsets[i], Tag: openTags[i], Message: "tag is never closed"})
+	}
+
+	return problems
+}
+
+// validateAttribute returns a description of what is wrong with a tag
+// attribute, or an empty string if it is valid.
+func validateAttribute(key string, val string, aliases map[string]int) string {
+
+	if len(val) == 0 {
+		return fmt.Sprintf(`attribute "%s" has no value`, key)
+	}
+
+	switch key {
+	case "fg", "bg":
+		if num, err := strconv.Atoi(val); err == nil {
+			if num < 0 || num > 255 {
+				return fmt.Sprintf(`color "%s" out of allowable range for "%s"`, val, key)
+			}
+			return ""
+		}
+		if _, ok := aliases[val]; !ok {
+			return fmt.Sprintf(`unknown color "%s" for "%s"`, val, key)
+		}
+	case "position":
+		if _, ok := positionMap[val]; ok {
+			return ""
+		}
+		posArr := strings.Split(val, ",")
+		if len(posArr) != 2 {
+			return fmt.Sprintf(`invalid position "%s"`, val)
+		}
+		for _, pos := range posArr {
+			if num, err := strconv.Atoi(pos); err != nil || num < 0 || num > posMax {
+				return fmt.Sprintf(`invalid position "%s"`, val)
+			}
+		}
+	case "clear":
+		if _, ok := clearMap[val]; !ok {
+			return fmt.Sprintf(`unknown clear mode "%s"`, val)
+		}
+	default:
+		return fmt.Sprintf(`unknown attribute "%s"`, key)
+	}
+
+	return ""
+}
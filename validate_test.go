@@ -0,0 +1,48 @@
+package ansitags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateValid(t *testing.T) {
+	assert.Nil(t, Validate("plain text"))
+	assert.Nil(t, Validate(`<ansi fg="red" bg=4>a <ansi position="1,2" clear=all>b</ansi></ansi>`))
+	assert.Nil(t, Validate(`<ansi position="topleft">x</ansi>`))
+	assert.Nil(t, Validate(`<\ansi fg="mauve"> and <\/ansi> are escaped`))
+}
+
+func TestValidateProblems(t *testing.T) {
+	var tests = []struct {
+		name    string
+		input   string
+		offset  int
+		message string
+	}{
+		{"unknown attribute", `<ansi bold=true>x</ansi>`, 0, `unknown attribute "bold"`},
+		{"unknown color", `ab<ansi fg="mauve">x</ansi>`, 2, `unknown color "mauve" for "fg"`},
+		{"color range", `<ansi bg=256>x</ansi>`, 0, `color "256" out of allowable range for "bg"`},
+		{"empty value", `<ansi fg="">x</ansi>`, 0, `attribute "fg" has no value`},
+		{"position", `<ansi position=1>x</ansi>`, 0, `invalid position "1"`},
+		{"clear", `<ansi clear=everything>x</ansi>`, 0, `unknown clear mode "everything"`},
+		{"stray close", `x</ansi>`, 1, "close tag without a matching open tag"},
+		{"never closed", `x<ansi fg=red>y`, 1, "tag is never closed"},
+		{"incomplete", `x <ansi fg=red`, 2, "incomplete tag"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			problems := Validate(tc.input)
+			if assert.Equal(t, 1, len(problems), "%v", problems) {
+				assert.Equal(t, tc.offset, problems[0].Offset)
+				assert.Equal(t, tc.message, problems[0].Message)
+			}
+		})
+	}
+}
+
+func TestValidationErrorString(t *testing.T) {
+	problems := Validate(`<ansi fg=mauve>x</ansi>`)
+	assert.Equal(t, `offset 0: unknown color "mauve" for "fg": <ansi fg=mauve>`, problems[0].Error())
+}
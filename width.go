@@ -0,0 +1,232 @@
+package ansitags
+
+import (
+	"sort"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// tabStop is the distance between tab stops used when measuring tabs.
+	// Read and written atomically.
+	tabStop int32 = 8
+
+	// wideRanges are the East Asian Wide and Fullwidth code points, plus
+	// emoji that are displayed in emoji presentation by default.
+	wideRanges = [][2]rune{
+		{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC},
+		{0x23F0, 0x23F0}, {0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615},
+		{0x2648, 0x2653}, {0x267F, 0x267F}, {0x2693, 0x2693}, {0x26A1, 0x26A1},
+		{0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5}, {0x26CE, 0x26CE},
+		{0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+		{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B},
+		{0x2728, 0x2728}, {0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755},
+		{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27B0, 0x27B0}, {0x27BF, 0x27BF},
+		{0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55}, {0x2E80, 0x303E},
+		{0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+		{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19},
+		{0xFE30, 0xFE6F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x16FE0, 0x16FE4},
+		{0x17000, 0x18AFF}, {0x1B000, 0x1B2FF}, {0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF},
+		{0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F200, 0x1F202}, {0x1F210, 0x1F23B},
+		{0x1F240, 0x1F248}, {0x1F250, 0x1F251}, {0x1F260, 0x1F265}, {0x1F300, 0x1F320},
+		{0x1F32D, 0x1F335}, {0x1F337, 0x1F37C}, {0x1F37E, 0x1F393}, {0x1F3A0, 0x1F3CA},
+		{0x1F3CF, 0x1F3D3}, {0x1F3E0, 0x1F3F0}, {0x1F3F4, 0x1F3F4}, {0x1F3F8, 0x1F43E},
+		{0x1F440, 0x1F440}, {0x1F442, 0x1F4FC}, {0x1F4FF, 0x1F53D}, {0x1F54B, 0x1F54E},
+		{0x1F550, 0x1F567}, {0x1F57A, 0x1F57A}, {0x1F595, 0x1F596}, {0x1F5A4, 0x1F5A4},
+		{0x1F5FB, 0x1F64F}, {0x1F680, 0x1F6C5}, {0x1F6CC, 0x1F6CC}, {0x1F6D0, 0x1F6D2},
+		{0x1F6D5, 0x1F6D7}, {0x1F6EB, 0x1F6EC}, {0x1F6F4, 0x1F6FC}, {0x1F7E0, 0x1F7EB},
+		{0x1F90C, 0x1F93A}, {0x1F93C, 0x1F945}, {0x1F947, 0x1F9FF}, {0x1FA70, 0x1FAFF},
+		{0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+	}
+)
+
+const (
+	zeroWidthJoiner   rune = 0x200D
+	variationText     rune = 0xFE0E
+	variationEmoji    rune = 0xFE0F
+	regionalIndicator rune = 0x1F1E6 // first of the 26 regional indicator symbols
+)
+
+// SetTabStop sets the distance between tab stops used when measuring and
+// splitting text that contains tabs. The default is 8.
+func SetTabStop(width int) {
+	if width < 1 {
+		width = 1
+	}
+	atomic.StoreInt32(&tabStop, int32(width))
+}
+
+// VisibleWidth returns the number of terminal columns a tagged string takes
+// up once parsed. Tags are ignored, wide (e.g. CJK) characters and emoji count
+// as 2 columns, combining marks and other zero width characters as 0, and
+// tabs advance to the next tab stop. If the string has several lines the
+// width of the widest line is returned.
+func VisibleWidth(str string) int {
+
+	widest := 0
+	col := 0
+
+	for _, tok := range tokenize(str) {
+		if tok.kind != tokenText {
+			continue
+		}
+		for i := 0; i < len(tok.raw); {
+			size := clusterSize(tok.raw[i:])
+			if tok.raw[i+size-1] == '\n' { // \n or \r\n
+				col = 0
+			} else {
+				col += clusterWidth(tok.raw[i:i+size], col)
+			}
+			if col > widest {
+				widest = col
+			}
+			i += size
+		}
+	}
+
+	return widest
+}
+
+// runeWidth returns the number of columns a single rune occupies.
+func runeWidth(r rune) int {
+
+	if r < 0x20 || (r >= 0x7F && r < 0xA0) {
+		return 0
+	}
+	if isMark(r) {
+		return 0
+	}
+	if isWide(r) {
+		return 2
+	}
+	return 1
+}
+
+// isMark reports whether r is a zero width character that attaches to the
+// character before it: combining marks, format characters such as the zero
+// width joiner and variation selectors, and Hangul medial vowels.
+func isMark(r rune) bool {
+	if r < 0x300 {
+		return r == 0xAD // soft hyphen
+	}
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || (r >= 0x1160 && r <= 0x11FF)
+}
+
+func isWide(r rune) bool {
+	if r < wideRanges[0][0] {
+		return false
+	}
+	i := sort.Search(len(wideRanges), func(i int) bool {
+		return wideRanges[i][1] >= r
+	})
+	return i < len(wideRanges) && r >= wideRanges[i][0]
+}
+
+// clusterSize returns the byte length of the grapheme cluster at the start
+// of tagged text, where an escaped tagStart is a single cluster.
+func clusterSize(str string) int {
+	if len(str) > 1 && str[0] == tagStart && str[1] == tagEscape {
+		return 2
+	}
+	return graphemeSize(str)
+}
+
+// graphemeSize returns the byte length of the grapheme cluster at the start
+// of str: a base character followed by any combining marks, variation
+// selectors, emoji modifiers and zero width joined characters, or a pair of
+// regional indicators (a flag).
+func graphemeSize(str string) int {
+
+	base, size := utf8.DecodeRuneInString(str)
+	if base == '\r' && len(str) > 1 && str[1] == '\n' {
+		return 2
+	}
+	if base < 0x20 || (base >= 0x7F && base < 0xA0) {
+		return size
+	}
+	if base < 0x80 && (len(str) == 1 || str[1] < 0x80) {
+		// Fast path for ASCII followed by ASCII
+		return 1
+	}
+
+	joined := base == zeroWidthJoiner
+	flag := base >= regionalIndicator && base <= regionalIndicator+25
+
+	for size < len(str) {
+		r, n := utf8.DecodeRuneInString(str[size:])
+
+		switch {
+		case joined && r > 0x20:
+		case isMark(r):
+		case r >= 0x1F3FB && r <= 0x1F3FF: // emoji skin tone modifiers
+		case flag && r >= regionalIndicator && r <= regionalIndicator+25:
+			flag = false
+		default:
+			return size
+		}
+
+		joined = r == zeroWidthJoiner
+		size += n
+	}
+
+	return size
+}
+
+// clusterWidth returns the number of columns a grapheme cluster occupies when
+// it starts at column col. Only tabs depend on the column.
+func clusterWidth(cluster string, col int) int {
+
+	if len(cluster) == 1 {
+		switch {
+		case cluster[0] == '\t':
+			stop := int(atomic.LoadInt32(&tabStop))
+			return stop - col%stop
+		case cluster[0] < 0x20 || cluster[0] == 0x7F:
+			return 0
+		}
+		return 1
+	}
+
+	base, size := utf8.DecodeRuneInString(cluster)
+	width := runeWidth(base)
+
+	for size < len(cluster) {
+		r, n := utf8.DecodeRuneInString(cluster[size:])
+		switch {
+		case r == variationEmoji && width == 1:
+			width = 2
+		case r == variationText && width == 2:
+			width = 1
+		case base >= regionalIndicator && base <= regionalIndicator+25:
+			width = 2
+		}
+		size += n
+	}
+
+	return width
+}
+
+// textWidth returns the number of columns the text between tags advances
+// when it is written starting at column col.
+func textWidth(text string, col int) int {
+	start := col
+	for i := 0; i < len(text); {
+		size := clusterSize(text[i:])
+		col += clusterWidth(text[i:i+size], col)
+		i += size
+	}
+	return col - start
+}
+
+// plainWidth is textWidth for text that has already been unescaped, such as
+// the text of a Run.
+func plainWidth(text string, col int) int {
+	start := col
+	for i := 0; i < len(text); {
+		size := graphemeSize(text[i:])
+		col += clusterWidth(text[i:i+size], col)
+		i += size
+	}
+	return col - start
+}
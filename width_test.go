@@ -0,0 +1,70 @@
+package ansitags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVisibleWidth(t *testing.T) {
+
+	tests := map[string]struct {
+		input string
+		width int
+	}{
+		"Empty":           {"", 0},
+		"ASCII":           {"Hello", 5},
+		"Tags":            {`<ansi fg="red">Hello</ansi> World`, 11},
+		"Accented":        {"café", 4},
+		"Combining":       {"cafe\u0301", 4},
+		"BlockElements":   {"█▓▒░", 4},
+		"CJK":             {"日本語", 6},
+		"Fullwidth":       {"ＡＢ", 4},
+		"Emoji":           {"🙂", 2},
+		"EmojiModifier":   {"👍🏽", 2},
+		"ZWJSequence":     {"👩‍💻", 2},
+		"VariationEmoji":  {"☺️", 2},
+		"VariationText":   {"☺", 1},
+		"Flag":            {"🇳🇿", 2},
+		"ZeroWidthSpace":  {"a\u200bb", 2},
+		"ControlChars":    {"a\x07b", 2},
+		"Tab":             {"ab\tc", 9},
+		"TabAfterTag":     {`ab<ansi fg="red">` + "\tc</ansi>", 9},
+		"WidestLine":      {"abc\n日本語\nab", 6},
+		"TabAfterNewline": {"abcdefghij\n\tx", 10},
+		"CRLF":            {"abc\r\ndef", 3},
+		"TabAfterCRLF":    {"abcdefghij\r\n\tx", 10},
+		"Escaped":         {`<\ansi fg="red">`, 15},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.width, VisibleWidth(test.input))
+		})
+	}
+}
+
+func TestSetTabStop(t *testing.T) {
+
+	SetTabStop(4)
+	defer SetTabStop(8)
+
+	assert.Equal(t, 4, VisibleWidth("\t"))
+	assert.Equal(t, 8, VisibleWidth("abcd\t"))
+	assert.Equal(t, 5, VisibleWidth("ab\tc"))
+
+	SetTabStop(0)
+	assert.Equal(t, 3, VisibleWidth("a\t\t"))
+}
+
+func TestClusterSize(t *testing.T) {
+
+	assert.Equal(t, 1, clusterSize("ab"))
+	assert.Equal(t, 3, clusterSize("éx"))
+	assert.Equal(t, len("👩‍💻"), clusterSize("👩‍💻!"))
+	assert.Equal(t, len("🇳🇿"), clusterSize("🇳🇿🇳🇿"))
+	assert.Equal(t, 2, clusterSize("\r\n"))
+	assert.Equal(t, 1, clusterSize("\n\u0301"))
+	assert.Equal(t, 2, clusterSize(`<\ansi`))
+	assert.Equal(t, 1, graphemeSize(`<\ansi`))
+}
@@ -0,0 +1,380 @@
+package ansitags
+
+import "strings"
+
+// LongWordMode selects what Wrap does with a word wider than the line.
+type LongWordMode uint8
+
+const (
+	LongWordBreak     LongWordMode = iota // break the word at the line width
+	LongWordHyphenate                     // break the word, ending each broken line with a hyphen
+	LongWordOverflow                      // keep the word whole on its own line, overflowing the width
+)
+
+// WrapOptions controls how Wrap lays out text.
+type WrapOptions struct {
+	PreserveSpaces bool         // keep runs of spaces as written instead of collapsing them to one
+	FirstIndent    int          // spaces before the first line of each paragraph
+	HangingIndent  int          // spaces before every other line of each paragraph
+	Prefix         string       // tagged text written at the start of every line, e.g. `<ansi fg=cyan>|</ansi> `
+	LongWords      LongWordMode // how to handle words wider than the line
+	Justify        bool         // widen the gaps between words so every line but the last of a paragraph fills width
+}
+
+// wrapPart is a tag or a piece of text inside a wrapItem.
+type wrapPart struct {
+	kind tokenKind
+	raw  string
+}
+
+// wrapItem is a word or the gap of spaces between two words, along with any
+// tags that sit inside it.
+type wrapItem struct {
+	parts []wrapPart
+	space bool
+}
+
+// wrapLine is one line of output before it is rendered.
+type wrapLine struct {
+	items  []wrapItem
+	indent int
+}
+
+// Wrap word wraps a tagged string to lines of at most width visible columns.
+// Existing line breaks are kept, so each paragraph is wrapped on its own,
+// and runs of spaces are collapsed to one unless opts.PreserveSpaces is set.
+// Spaces at the point a line is broken are dropped. Words longer than the
+// line are broken or left to overflow, depending on opts.LongWords. A width
+// of 0 or less only splits on line breaks.
+//
+// With opts.Justify set, the spaces between words are widened so that every
+// line except the last of each paragraph is exactly width columns wide. The
+// extra spaces are added to the existing gaps, so they take on the colors of
+// the text around them.
+//
+// The indents and the prefix count towards width. The prefix is written as
+// is at the start of every line, outside of any tags open in str.
+//
+// Each returned line closes any tags still open at its end and reopens them
+// at the start of the next line, so every line can be parsed on its own.
+//
+// Usage:
+//
+//	for _, line := range ansitags.Wrap(description, 80, ansitags.WrapOptions{}) {
+//		fmt.Println(ansitags.Parse(line))
+//	}
+func Wrap(str string, width int, opts WrapOptions) []string {
+
+	prefixWidth := VisibleWidth(opts.Prefix)
+
+	var lines []wrapLine
+	for _, items := range wrapParagraphs(str, opts.PreserveSpaces) {
+		lines = append(lines, layoutParagraph(items, prefixWidth, width, opts)...)
+	}
+
+	return renderLines(lines, opts.Prefix)
+}
+
+// wrapParagraphs splits str on line breaks and each paragraph into words and
+// gaps. Open tags are attached to the item that follows them and close tags
+// to the item before them, so a tag is never left alone at the end or start
+// of a line.
+func wrapParagraphs(str string, preserveSpaces bool) [][]wrapItem {
+
+	var paragraphs [][]wrapItem
+	var items []wrapItem
+	var pending []wrapPart
+
+	addText := func(text string, space bool) {
+		if n := len(items); n > 0 && items[n-1].space == space && (space || len(pending) == 0) {
+			items[n-1].parts = append(items[n-1].parts, pending...)
+			pending = pending[:0]
+			if !space || preserveSpaces {
+				items[n-1].parts = append(items[n-1].parts, wrapPart{kind: tokenText, raw: text})
+			}
+			return
+		}
+		parts := append([]wrapPart(nil), pending...)
+		pending = pending[:0]
+		if space && !preserveSpaces {
+			text = " "
+		}
+		items = append(items, wrapItem{parts: append(parts, wrapPart{kind: tokenText, raw: text}), space: space})
+	}
+
+	for _, tok := range tokenize(str) {
+		switch tok.kind {
+		case tokenOpen:
+			pending = append(pending, wrapPart{kind: tok.kind, raw: tok.raw})
+		case tokenClose:
+			if n := len(items); n > 0 && len(pending) == 0 {
+				items[n-1].parts = append(items[n-1].parts, wrapPart{kind: tok.kind, raw: tok.raw})
+			} else {
+				pending = append(pending, wrapPart{kind: tok.kind, raw: tok.raw})
+			}
+		default:
+			text := strings.ReplaceAll(tok.raw, "\r\n", "\n")
+			for len(text) > 0 {
+				if text[0] == '\n' {
+					paragraphs = append(paragraphs, items)
+					items = nil
+					text = text[1:]
+					continue
+				}
+
+				end := 0
+				space := text[0] == ' '
+				for end < len(text) && text[end] != '\n' && (text[end] == ' ') == space {
+					end++
+				}
+
+				addText(text[:end], space)
+				text = text[end:]
+			}
+		}
+	}
+
+	if len(pending) > 0 {
+		items = append(items, wrapItem{parts: pending})
+	}
+
+	return append(paragraphs, items)
+}
+
+// layoutParagraph places the words and gaps of a paragraph on lines. Columns
+// are counted from the start of the line, with the first offset columns
+// taken by the prefix.
+func layoutParagraph(items []wrapItem, offset int, width int, opts WrapOptions) []wrapLine {
+
+	var lines []wrapLine
+
+	line := wrapLine{indent: opts.FirstIndent}
+	start := offset + line.indent
+	col := start
+
+	newLine := func() {
+		if opts.Justify && width > col {
+			line.justify(width - col)
+		}
+		lines = append(lines, line)
+		line = wrapLine{indent: opts.HangingIndent}
+		start = offset + line.indent
+		col = start
+	}
+
+	place := func(item wrapItem) {
+		col += item.width(col)
+		line.items = append(line.items, item)
+	}
+
+	fits := func(w int) bool {
+		return width <= 0 || col+w <= width
+	}
+
+	// gap is the spacing waiting to be placed before the next word
+	var gap *wrapItem
+
+	for i := range items {
+		item := items[i]
+
+		if item.space {
+			if col == start && len(lines) == 0 && !opts.PreserveSpaces {
+				// Collapsed leading spaces are dropped
+				place(item.tagsOnly())
+				continue
+			}
+			gap = &items[i]
+			continue
+		}
+
+		if gap != nil {
+			gapWidth := gap.width(col)
+			if fits(gapWidth + item.width(col+gapWidth)) {
+				place(*gap)
+			} else {
+				place(gap.tagsOnly())
+				if col > start {
+					newLine()
+				}
+			}
+			gap = nil
+		} else if col > start && !fits(item.width(col)) {
+			newLine()
+		}
+
+		if !fits(item.width(col)) && opts.LongWords != LongWordOverflow {
+			// The word can't fit on a line of its own, break it to the room
+			// left on this line and then on each line after it
+			for {
+				room := width - col
+				hyphenate := opts.LongWords == LongWordHyphenate && room > 1
+				if hyphenate {
+					room--
+				}
+
+				chunk, rest, ok := item.breakAt(room)
+				if !ok {
+					break
+				}
+				if hyphenate {
+					chunk.parts = append(chunk.parts, wrapPart{kind: tokenText, raw: "-"})
+				}
+				place(chunk)
+				newLine()
+				item = rest
+				if fits(item.width(col)) {
+					break
+				}
+			}
+		}
+
+		place(item)
+	}
+
+	if gap != nil {
+		if opts.PreserveSpaces && fits(gap.width(col)) {
+			place(*gap)
+		} else {
+			place(gap.tagsOnly())
+		}
+	}
+
+	return append(lines, line)
+}
+
+// renderLines writes out laid out lines after the prefix and indent,
+// reopening the tags that are open at the start of each line and closing the
+// ones still open at its end.
+func renderLines(lines []wrapLine, prefix string) []string {
+
+	var stack []string
+	var out strings.Builder
+
+	result := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		if len(line.items) == 0 {
+			result = append(result, prefix)
+			continue
+		}
+
+		out.Reset()
+		out.WriteString(prefix)
+		for i := 0; i < line.indent; i++ {
+			out.WriteByte(' ')
+		}
+		for _, tag := range stack {
+			out.WriteString(tag)
+		}
+
+		for _, item := range line.items {
+			for _, part := range item.parts {
+				switch part.kind {
+				case tokenOpen:
+					stack = append(stack, part.raw)
+				case tokenClose:
+					if len(stack) > 0 {
+						stack = stack[:len(stack)-1]
+					}
+				}
+				out.WriteString(part.raw)
+			}
+		}
+
+		for range stack {
+			out.WriteString("</ansi>")
+		}
+
+		result = append(result, out.String())
+	}
+
+	return result
+}
+
+// justify spreads extra columns of space over the gaps between the words of
+// the line, giving the leftmost gaps one more when they don't divide evenly.
+func (l *wrapLine) justify(extra int) {
+
+	var gaps []int
+	for i, item := range l.items {
+		if i > 0 && item.space {
+			gaps = append(gaps, i)
+		}
+	}
+
+	if len(gaps) == 0 {
+		return
+	}
+
+	for n, i := range gaps {
+		add := extra / len(gaps)
+		if n < extra%len(gaps) {
+			add++
+		}
+
+		parts := append([]wrapPart(nil), l.items[i].parts...)
+		for j := len(parts) - 1; j >= 0; j-- {
+			if parts[j].kind == tokenText {
+				parts[j].raw += strings.Repeat(" ", add)
+				break
+			}
+		}
+		l.items[i].parts = parts
+	}
+}
+
+// width returns the number of columns the item takes up when it starts at
+// column col.
+func (w wrapItem) width(col int) int {
+	start := col
+	for _, part := range w.parts {
+		if part.kind == tokenText {
+			col += textWidth(part.raw, col)
+		}
+	}
+	return col - start
+}
+
+// tagsOnly returns the item with its text removed, for spacing that is
+// dropped at a line break but may still hold tags.
+func (w wrapItem) tagsOnly() wrapItem {
+	tags := wrapItem{}
+	for _, part := range w.parts {
+		if part.kind != tokenText {
+			tags.parts = append(tags.parts, part)
+		}
+	}
+	return tags
+}
+
+// breakAt splits a word after as many grapheme clusters as fit in width
+// columns, always at least one, returning the two halves. ok is false if the
+// whole word fits.
+func (w wrapItem) breakAt(width int) (chunk wrapItem, rest wrapItem, ok bool) {
+
+	col := 0
+
+	for p, part := range w.parts {
+		if part.kind != tokenText {
+			continue
+		}
+
+		for i := 0; i < len(part.raw); {
+			size := clusterSize(part.raw[i:])
+			clusterW := clusterWidth(part.raw[i:i+size], col)
+			if col > 0 && col+clusterW > width {
+				chunk.parts = append(chunk.parts, w.parts[:p]...)
+				if i > 0 {
+					chunk.parts = append(chunk.parts, wrapPart{kind: tokenText, raw: part.raw[:i]})
+				}
+				rest.parts = append(rest.parts, wrapPart{kind: tokenText, raw: part.raw[i:]})
+				rest.parts = append(rest.parts, w.parts[p+1:]...)
+				return chunk, rest, true
+			}
+			col += clusterW
+			i += size
+		}
+	}
+
+	return w, wrapItem{}, false
+}
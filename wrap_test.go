@@ -0,0 +1,186 @@
+package ansitags
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapBasic(t *testing.T) {
+	result := Wrap("The quick brown fox jumps over the lazy dog", 10, WrapOptions{})
+	assert.Equal(t, []string{"The quick", "brown fox", "jumps over", "the lazy", "dog"}, result)
+}
+
+func TestWrapNoWrapNeeded(t *testing.T) {
+	assert.Equal(t, []string{"short"}, Wrap("short", 10, WrapOptions{}))
+	assert.Equal(t, []string{""}, Wrap("", 10, WrapOptions{}))
+}
+
+func TestWrapParagraphs(t *testing.T) {
+	input := "First paragraph is here.\n\nSecond one\r\nThird"
+	result := Wrap(input, 12, WrapOptions{})
+	assert.Equal(t, []string{"First", "paragraph is", "here.", "", "Second one", "Third"}, result)
+}
+
+func TestWrapWidthDoesNotCarryAcrossLines(t *testing.T) {
+	result := Wrap("abcdefgh\nab cd", 8, WrapOptions{})
+	assert.Equal(t, []string{"abcdefgh", "ab cd"}, result)
+}
+
+func TestWrapNoWidth(t *testing.T) {
+	result := Wrap("one two three\nfour", 0, WrapOptions{})
+	assert.Equal(t, []string{"one two three", "four"}, result)
+}
+
+func TestWrapCollapseSpaces(t *testing.T) {
+	result := Wrap("  lots   of    space  ", 20, WrapOptions{})
+	assert.Equal(t, []string{"lots of space"}, result)
+}
+
+func TestWrapPreserveSpaces(t *testing.T) {
+	result := Wrap("  lots   of    space  ", 30, WrapOptions{PreserveSpaces: true})
+	assert.Equal(t, []string{"  lots   of    space  "}, result)
+
+	// Spaces at a line break are still dropped
+	result = Wrap("one   two   three", 9, WrapOptions{PreserveSpaces: true})
+	assert.Equal(t, []string{"one   two", "three"}, result)
+}
+
+func TestWrapLongWord(t *testing.T) {
+	result := Wrap("a supercalifragilistic word", 10, WrapOptions{})
+	assert.Equal(t, []string{"a", "supercalif", "ragilistic", "word"}, result)
+}
+
+func TestWrapReopensTags(t *testing.T) {
+	input := `<ansi fg="red">The quick brown</ansi> fox <ansi fg="blue">jumps <ansi bg="white">over the</ansi> lazy</ansi> dog`
+	result := Wrap(input, 10, WrapOptions{})
+
+	assert.Equal(t, []string{
+		`<ansi fg="red">The quick</ansi>`,
+		`<ansi fg="red">brown</ansi> fox`,
+		`<ansi fg="blue">jumps <ansi bg="white">over</ansi></ansi>`,
+		`<ansi fg="blue"><ansi bg="white">the</ansi> lazy</ansi>`,
+		`dog`,
+	}, result)
+}
+
+func TestWrapTagsAcrossParagraphs(t *testing.T) {
+	result := Wrap("<ansi fg=red>one\ntwo</ansi>\nthree", 10, WrapOptions{})
+	assert.Equal(t, []string{"<ansi fg=red>one</ansi>", "<ansi fg=red>two</ansi>", "three"}, result)
+}
+
+func TestWrapLinesParseable(t *testing.T) {
+	input := `<ansi fg="yellow">This is some <ansi fg="black">long as heck</ansi> text that wraps</ansi> over several lines`
+
+	for width := 1; width <= 40; width++ {
+		for _, line := range Wrap(input, width, WrapOptions{}) {
+			assert.LessOrEqual(t, VisibleWidth(line), width, "width=%d line=%q", width, line)
+			assert.Equal(t, strings.Count(line, "<ansi "), strings.Count(line, "</ansi>"), "width=%d line=%q", width, line)
+		}
+	}
+}
+
+func TestWrapWide(t *testing.T) {
+	result := Wrap("日本語 の テキスト", 6, WrapOptions{})
+	assert.Equal(t, []string{"日本語", "の", "テキス", "ト"}, result)
+}
+
+func TestWrapIndents(t *testing.T) {
+	opts := WrapOptions{FirstIndent: 4, HangingIndent: 2}
+	result := Wrap("The quick brown fox jumps\nover the lazy dog", 12, opts)
+	assert.Equal(t, []string{"    The", "  quick", "  brown fox", "  jumps", "    over the", "  lazy dog"}, result)
+}
+
+func TestWrapPrefix(t *testing.T) {
+	opts := WrapOptions{Prefix: `<ansi fg=cyan>|</ansi> `}
+	result := Wrap(`<ansi fg=red>The quick brown fox</ansi>`+"\n\nbye", 12, opts)
+	assert.Equal(t, []string{
+		`<ansi fg=cyan>|</ansi> <ansi fg=red>The quick</ansi>`,
+		`<ansi fg=cyan>|</ansi> <ansi fg=red>brown fox</ansi>`,
+		`<ansi fg=cyan>|</ansi> `,
+		`<ansi fg=cyan>|</ansi> bye`,
+	}, result)
+}
+
+func TestWrapPrefixAndIndent(t *testing.T) {
+	opts := WrapOptions{Prefix: "> ", HangingIndent: 2}
+	result := Wrap("one two three four", 10, opts)
+	assert.Equal(t, []string{"> one two", ">   three", ">   four"}, result)
+	for _, line := range result {
+		assert.LessOrEqual(t, VisibleWidth(line), 10)
+	}
+}
+
+func TestWrapLongWordModes(t *testing.T) {
+	input := "a <ansi fg=red>supercalifragilistic</ansi> word"
+
+	result := Wrap(input, 10, WrapOptions{LongWords: LongWordBreak})
+	assert.Equal(t, []string{"a", "<ansi fg=red>supercalif</ansi>", "<ansi fg=red>ragilistic</ansi>", "word"}, result)
+
+	result = Wrap(input, 10, WrapOptions{LongWords: LongWordHyphenate})
+	assert.Equal(t, []string{"a", "<ansi fg=red>supercali-</ansi>", "<ansi fg=red>fragilist-</ansi>", "<ansi fg=red>ic</ansi> word"}, result)
+
+	result = Wrap(input, 10, WrapOptions{LongWords: LongWordOverflow})
+	assert.Equal(t, []string{"a", "<ansi fg=red>supercalifragilistic</ansi>", "word"}, result)
+}
+
+func TestWrapHyphenateWithIndent(t *testing.T) {
+	result := Wrap("abcdefgh", 6, WrapOptions{FirstIndent: 2, HangingIndent: 2, LongWords: LongWordHyphenate})
+	assert.Equal(t, []string{"  abc-", "  def-", "  gh"}, result)
+}
+
+func TestWrapBreakHangingIndent(t *testing.T) {
+	// Every line of a broken word gets the room left after its own indent
+	result := Wrap("abcdefghijkl", 6, WrapOptions{HangingIndent: 4})
+	assert.Equal(t, []string{"abcdef", "    gh", "    ij", "    kl"}, result)
+
+	result = Wrap("x abcdefghij", 8, WrapOptions{FirstIndent: 1, HangingIndent: 3, Prefix: "> ", LongWords: LongWordHyphenate})
+	assert.Equal(t, []string{">  x", ">    ab-", ">    cd-", ">    ef-", ">    gh-", ">    ij"}, result)
+	for _, line := range result {
+		assert.LessOrEqual(t, VisibleWidth(line), 8)
+	}
+}
+
+func TestWrapJustify(t *testing.T) {
+	input := "The quick brown fox jumps over the lazy dog\nA new paragraph starts here"
+	result := Wrap(input, 16, WrapOptions{Justify: true})
+
+	assert.Equal(t, []string{
+		"The  quick brown",
+		"fox  jumps  over",
+		"the lazy dog",
+		"A  new paragraph",
+		"starts here",
+	}, result)
+}
+
+func TestWrapJustifyKeepsColors(t *testing.T) {
+	input := `<ansi fg="red">The quick</ansi> brown <ansi bg="blue">fox jumps over</ansi> the lazy dog`
+	result := Wrap(input, 16, WrapOptions{Justify: true})
+
+	assert.Equal(t, []string{
+		`<ansi fg="red">The  quick</ansi> brown`,
+		`<ansi bg="blue">fox  jumps  over</ansi>`,
+		`the lazy dog`,
+	}, result)
+
+	for _, line := range result[:len(result)-1] {
+		assert.Equal(t, 16, VisibleWidth(line))
+	}
+}
+
+func TestWrapJustifyWithPrefix(t *testing.T) {
+	result := Wrap("one two three four five", 12, WrapOptions{Justify: true, Prefix: "| ", HangingIndent: 1})
+	assert.Equal(t, []string{"| one    two", "|  three", "|  four five"}, result)
+
+	// A single word line has no gaps to widen
+	assert.Equal(t, 8, VisibleWidth(result[1]))
+}
+
+func BenchmarkWrap(b *testing.B) {
+	input := `<ansi fg="yellow">This is some <ansi fg="black">long as heck</ansi> text</ansi> that keeps going`
+	for n := 0; n < b.N; n++ {
+		Wrap(input, 10, WrapOptions{})
+	}
+}